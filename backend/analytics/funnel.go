@@ -0,0 +1,227 @@
+package main
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	pb "event-analytics/proto/event-analytics/proto"
+
+	"github.com/lib/pq"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultFunnelRange  = 24 * time.Hour
+	defaultFunnelWindow = 24 * time.Hour
+	maxFunnelWindow     = 30 * 24 * time.Hour
+	maxFunnelRange      = 31 * 24 * time.Hour
+	minFunnelSteps      = 2
+	maxFunnelSteps      = 10
+)
+
+// funnelStep is a step of the request compiled for matching, in Postgres as a LIKE pattern
+// and in Go as a regexp, both empty when the step matches any page.
+type funnelStep struct {
+	like      string
+	page      *regexp.Regexp
+	eventType string
+}
+
+func compileFunnelStep(step *pb.FunnelStep) funnelStep {
+	compiled := funnelStep{eventType: step.EventType}
+	if step.PageUrl != "" {
+		like := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`, `*`, `%`).Replace(step.PageUrl)
+		pattern := strings.ReplaceAll(regexp.QuoteMeta(step.PageUrl), `\*`, `.*`)
+		compiled.like = like
+		compiled.page = regexp.MustCompile("^" + pattern + "$")
+	}
+	return compiled
+}
+
+func (f funnelStep) matches(e funnelEvent) bool {
+	if f.eventType != "" && f.eventType != e.eventType {
+		return false
+	}
+	return f.page == nil || f.page.MatchString(e.pageURL)
+}
+
+type funnelEvent struct {
+	pageURL   string
+	eventType string
+	at        time.Time
+}
+
+// GetFunnel counts the users who went through the steps in order within the window, starting
+// with an entry into the first step in the range. The events of one user at a time are read
+// from click_events, ordered by time, and walked in Go.
+func (s *server) GetFunnel(ctx context.Context, req *pb.FunnelRequest) (*pb.FunnelResponse, error) {
+	if len(req.Steps) < minFunnelSteps || len(req.Steps) > maxFunnelSteps {
+		return nil, status.Errorf(codes.InvalidArgument, "a funnel needs %d to %d steps", minFunnelSteps, maxFunnelSteps)
+	}
+
+	steps := make([]funnelStep, len(req.Steps))
+	likes := make([]string, len(req.Steps))
+	types := make([]string, len(req.Steps))
+	for i, step := range req.Steps {
+		if step.PageUrl == "" && step.EventType == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "step %d needs a page_url or an event_type", i+1)
+		}
+		// a pattern can't be normalized, its literal parts are matched as given
+		if !strings.Contains(step.PageUrl, "*") {
+			if err := normalizePage(&step.PageUrl); err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "step %d: %s", i+1, status.Convert(err).Message())
+			}
+		}
+		steps[i] = compileFunnelStep(step)
+		likes[i] = steps[i].like
+		types[i] = steps[i].eventType
+	}
+
+	window := defaultFunnelWindow
+	if req.Window != nil {
+		if err := req.Window.CheckValid(); err != nil || req.Window.AsDuration() <= 0 {
+			return nil, status.Error(codes.InvalidArgument, "window must be positive")
+		}
+		window = req.Window.AsDuration()
+	}
+	if window > maxFunnelWindow {
+		return nil, status.Errorf(codes.InvalidArgument, "window must be at most %v", maxFunnelWindow)
+	}
+
+	start, end, err := timeRange(req.Start, req.End, defaultFunnelRange)
+	if err != nil {
+		return nil, err
+	}
+	if end.Sub(start) > maxFunnelRange {
+		return nil, status.Errorf(codes.InvalidArgument, "range must be at most %v", maxFunnelRange)
+	}
+
+	// users entering the funnel just before the end get the whole window to finish it
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT e.user_id, e.page_url, e.event_type, e.time_stamp
+		FROM click_events e
+		WHERE e.time_stamp >= $1 AND e.time_stamp < $2
+		  AND EXISTS (
+			SELECT 1 FROM unnest($3::text[], $4::text[]) AS s(page_url, event_type)
+			WHERE (s.page_url = '' OR e.page_url LIKE s.page_url)
+			  AND (s.event_type = '' OR e.event_type = s.event_type)
+		  )
+		ORDER BY e.user_id, e.time_stamp, e.event_id
+	`, start, end.Add(window), pq.Array(likes), pq.Array(types))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		users  = make([]int64, len(steps))
+		gaps   = make([][]time.Duration, len(steps))
+		userID string
+		events []funnelEvent
+	)
+	tally := func() {
+		reached := walkFunnel(steps, events, start, end, window)
+		for i, at := range reached {
+			users[i]++
+			if i > 0 {
+				gaps[i] = append(gaps[i], at.Sub(reached[i-1]))
+			}
+		}
+		events = events[:0]
+	}
+	for rows.Next() {
+		var id string
+		var e funnelEvent
+		if err := rows.Scan(&id, &e.pageURL, &e.eventType, &e.at); err != nil {
+			return nil, err
+		}
+		if id != userID && len(events) > 0 {
+			tally()
+		}
+		userID = id
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	tally()
+
+	resp := &pb.FunnelResponse{
+		Window: durationpb.New(window),
+		Start:  timestamppb.New(start),
+		End:    timestamppb.New(end),
+	}
+	for i, step := range req.Steps {
+		result := &pb.FunnelStepResult{Step: step, Users: users[i]}
+		if users[0] > 0 {
+			result.ConversionPercent = percent(users[i], users[0])
+		}
+		if i == 0 {
+			result.StepConversionPercent = result.ConversionPercent
+		} else {
+			if users[i-1] > 0 {
+				result.StepConversionPercent = percent(users[i], users[i-1])
+				result.DropOffPercent = 100 - result.StepConversionPercent
+			}
+			result.MedianTimeFromPrevious = durationpb.New(median(gaps[i]))
+		}
+		resp.Steps = append(resp.Steps, result)
+	}
+	return resp, nil
+}
+
+// walkFunnel returns when a user reached each step, following the entry into the first step
+// that got furthest, the earliest one on a tie. Each later step is the first matching event
+// after the previous step, no later than the window after the entry.
+func walkFunnel(steps []funnelStep, events []funnelEvent, start, end time.Time, window time.Duration) []time.Time {
+	var best []time.Time
+	for i, entry := range events {
+		if entry.at.Before(start) || !entry.at.Before(end) || !steps[0].matches(entry) {
+			continue
+		}
+
+		reached := []time.Time{entry.at}
+		deadline := entry.at.Add(window)
+		for _, e := range events[i+1:] {
+			if e.at.After(deadline) {
+				break
+			}
+			if steps[len(reached)].matches(e) {
+				reached = append(reached, e.at)
+				if len(reached) == len(steps) {
+					break
+				}
+			}
+		}
+
+		if len(reached) > len(best) {
+			best = reached
+		}
+		if len(best) == len(steps) {
+			break
+		}
+	}
+	return best
+}
+
+func percent(part, whole int64) float64 {
+	return 100 * float64(part) / float64(whole)
+}
+
+func median(durations []time.Duration) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	mid := len(durations) / 2
+	if len(durations)%2 == 0 {
+		return (durations[mid-1] + durations[mid]) / 2
+	}
+	return durations[mid]
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	pb "event-analytics/proto/event-analytics/proto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCompileFunnelStep(t *testing.T) {
+	tests := []struct {
+		pageURL  string
+		wantLike string
+		match    []string
+		noMatch  []string
+	}{
+		{"", "", []string{"/anything"}, nil},
+		{"/pricing", "/pricing", []string{"/pricing"}, []string{"/pricing/", "/pricing/team"}},
+		{"/docs/*", "/docs/%", []string{"/docs/", "/docs/a/b"}, []string{"/docs", "/blog/docs/a"}},
+		{"/a_b%c", `/a\_b\%c`, []string{"/a_b%c"}, []string{"/axb%c", "/a_bxc"}},
+		{`/x\y.z`, `/x\\y.z`, []string{`/x\y.z`}, []string{`/x\yaz`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pageURL, func(t *testing.T) {
+			step := compileFunnelStep(&pb.FunnelStep{PageUrl: tt.pageURL})
+			if step.like != tt.wantLike {
+				t.Errorf("like = %q, want %q", step.like, tt.wantLike)
+			}
+			for _, page := range tt.match {
+				if !step.matches(funnelEvent{pageURL: page}) {
+					t.Errorf("%q does not match %q", tt.pageURL, page)
+				}
+			}
+			for _, page := range tt.noMatch {
+				if step.matches(funnelEvent{pageURL: page}) {
+					t.Errorf("%q matches %q", tt.pageURL, page)
+				}
+			}
+		})
+	}
+}
+
+func TestWalkFunnel(t *testing.T) {
+	base := time.Date(2025, 11, 10, 12, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return base.Add(time.Duration(minutes) * time.Minute) }
+	event := func(page string, minutes int) funnelEvent {
+		return funnelEvent{pageURL: page, eventType: "page_view", at: at(minutes)}
+	}
+
+	steps := []funnelStep{
+		compileFunnelStep(&pb.FunnelStep{PageUrl: "/home"}),
+		compileFunnelStep(&pb.FunnelStep{PageUrl: "/pricing"}),
+		compileFunnelStep(&pb.FunnelStep{PageUrl: "/signup", EventType: "click"}),
+	}
+	signup := func(minutes int) funnelEvent {
+		return funnelEvent{pageURL: "/signup", eventType: "click", at: at(minutes)}
+	}
+
+	// the range is [base, base+60m) and the window 30m
+	start, end, window := at(0), at(60), 30*time.Minute
+
+	tests := []struct {
+		name   string
+		events []funnelEvent
+		want   []time.Time
+	}{
+		{"no events", nil, nil},
+		{
+			"completes the funnel",
+			[]funnelEvent{event("/home", 0), event("/pricing", 5), signup(10)},
+			[]time.Time{at(0), at(5), at(10)},
+		},
+		{
+			"steps out of order only reach the first",
+			[]funnelEvent{event("/pricing", 0), event("/home", 5), signup(10)},
+			[]time.Time{at(5)},
+		},
+		{
+			"event type must match too",
+			[]funnelEvent{event("/home", 0), event("/pricing", 5), event("/signup", 10)},
+			[]time.Time{at(0), at(5)},
+		},
+		{
+			"each later step is the first match after the previous one",
+			[]funnelEvent{event("/home", 0), event("/pricing", 5), event("/pricing", 8), signup(10), signup(12)},
+			[]time.Time{at(0), at(5), at(10)},
+		},
+		{
+			"events at the same time count in order",
+			[]funnelEvent{event("/home", 0), event("/pricing", 0), signup(0)},
+			[]time.Time{at(0), at(0), at(0)},
+		},
+		{
+			"a step exactly at the deadline counts",
+			[]funnelEvent{event("/home", 0), event("/pricing", 30)},
+			[]time.Time{at(0), at(30)},
+		},
+		{
+			"a step past the deadline does not",
+			[]funnelEvent{event("/home", 0), {pageURL: "/pricing", eventType: "page_view", at: at(30).Add(time.Second)}},
+			[]time.Time{at(0)},
+		},
+		{
+			"a later entry that gets further wins",
+			[]funnelEvent{event("/home", 0), event("/home", 40), event("/pricing", 45)},
+			[]time.Time{at(40), at(45)},
+		},
+		{
+			"the earliest entry wins a tie",
+			[]funnelEvent{event("/home", 0), event("/pricing", 5), event("/home", 10), event("/pricing", 15)},
+			[]time.Time{at(0), at(5)},
+		},
+		{
+			"entries before the range are skipped",
+			[]funnelEvent{event("/home", -5), event("/pricing", 1), event("/home", 2), event("/pricing", 3)},
+			[]time.Time{at(2), at(3)},
+		},
+		{
+			"the range end is exclusive for entries",
+			[]funnelEvent{event("/home", 60), event("/pricing", 61)},
+			nil,
+		},
+		{
+			"later steps may fall after the range end",
+			[]funnelEvent{event("/home", 55), event("/pricing", 70), signup(80)},
+			[]time.Time{at(55), at(70), at(80)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := walkFunnel(steps, tt.events, start, end, window)
+			if !slices.EqualFunc(got, tt.want, time.Time.Equal) {
+				t.Errorf("walkFunnel = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetFunnelInvalidStep(t *testing.T) {
+	s := &server{}
+	_, err := s.GetFunnel(context.Background(), &pb.FunnelRequest{Steps: []*pb.FunnelStep{
+		{PageUrl: "/home"},
+		{PageUrl: "pricing"},
+	}})
+	if status.Code(err) != codes.InvalidArgument || !strings.Contains(err.Error(), "step 2") {
+		t.Errorf("GetFunnel error = %v, want InvalidArgument for step 2", err)
+	}
+}
@@ -9,68 +9,85 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"os/signal"
 	"strconv"
+	"syscall"
+	"time"
 
+	"event-analytics/internal/cache"
+	"event-analytics/internal/config"
+	"event-analytics/internal/event"
 	pb "event-analytics/proto/event-analytics/proto"
 
 	_ "github.com/lib/pq"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/redis/go-redis/v9"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	shutdownTimeout = config.GetDuration("SHUTDOWN_TIMEOUT", 25*time.Second)
+	// the processor invalidates counts it changes, the expiry bounds how long one it missed
+	// is served stale
+	countCacheTTL = config.GetDuration("COUNT_CACHE_TTL", 10*time.Minute)
 )
 
 type server struct {
 	pb.UnimplementedAnalyticsServiceServer
-	db *sql.DB
+	db     *sql.DB
+	counts *countsHub
 }
 
 var rdb = redis.NewClient(&redis.Options{
-	Addr: getRedisAddr(),
+	Addr: config.RedisAddr(),
 	DB:   0,
 })
 
-// Helper function to get Redis address
-func getRedisAddr() string {
-	addr := os.Getenv("REDIS_ADDR")
-	if addr == "" {
-		// Fallback to individual host:port if REDIS_ADDR not set
-		host := os.Getenv("REDIS_HOST")
-		port := os.Getenv("REDIS_PORT")
-		if host == "" {
-			host = "localhost"
-		}
-		if port == "" {
-			port = "6379"
-		}
-		addr = host + ":" + port
+func (s *server) GetEventCount(ctx context.Context, req *pb.EventCountRequest) (*pb.EventCountResponse, error) {
+	if err := normalizePage(&req.PageUrl); err != nil {
+		return nil, err
 	}
-	return addr
-}
 
-func (s *server) GetEventCount(ctx context.Context, req *pb.EventCountRequest) (*pb.EventCountResponse, error) {
 	var count int64
-	var CompactStr = req.UserId + "__+__" + req.PageUrl
+	var CompactStr = cache.CountKey(req.UserId, req.PageUrl, req.EventType)
 	val, err := rdb.Get(ctx, CompactStr).Result()
 
 	if err == redis.Nil {
 		fmt.Println("Key does not exist")
-		err = s.db.QueryRow(`
-        SELECT click_count FROM page_clicks 
-        WHERE user_id = $1 AND page_url = $2
-    `, req.UserId, req.PageUrl).Scan(&count)
-
+		// an empty event_type sums every type, a pair or page nobody clicked counts 0
+		var total sql.NullInt64
+		switch {
+		case req.UserId != "" && req.PageUrl != "":
+			err = s.db.QueryRow(`
+        SELECT SUM(click_count) FROM page_clicks 
+        WHERE user_id = $1 AND page_url = $2 AND ($3 = '' OR event_type = $3)
+    `, req.UserId, req.PageUrl, req.EventType).Scan(&total)
+		case req.PageUrl != "":
+			err = s.db.QueryRow(`SELECT SUM(click_count) FROM page_totals WHERE page_url = $1 AND ($2 = '' OR event_type = $2)`,
+				req.PageUrl, req.EventType).Scan(&total)
+		case req.UserId != "":
+			err = s.db.QueryRow(`SELECT SUM(click_count) FROM user_totals WHERE user_id = $1 AND ($2 = '' OR event_type = $2)`,
+				req.UserId, req.EventType).Scan(&total)
+		default:
+			return nil, status.Error(codes.InvalidArgument, "user_id or page_url is required")
+		}
 		if err != nil {
 			return nil, err
 		}
-		err = rdb.Set(ctx, CompactStr, count, 0).Err()
+		count = total.Int64
+
+		err = rdb.Set(ctx, CompactStr, count, countCacheTTL).Err()
 		if err != nil {
 			log.Fatalf("Error setting value: %v", err)
 		}
 
 		return &pb.EventCountResponse{
-			Count:   count,
-			UserId:  req.UserId,
-			PageUrl: req.PageUrl,
+			Count:     count,
+			UserId:    req.UserId,
+			PageUrl:   req.PageUrl,
+			EventType: req.EventType,
 		}, nil
 
 	} else if err != nil {
@@ -82,23 +99,39 @@ func (s *server) GetEventCount(ctx context.Context, req *pb.EventCountRequest) (
 		log.Fatalf("Error while caching")
 	}
 	return &pb.EventCountResponse{
-		Count:   count,
-		UserId:  req.UserId,
-		PageUrl: req.PageUrl,
+		Count:     count,
+		UserId:    req.UserId,
+		PageUrl:   req.PageUrl,
+		EventType: req.EventType,
 	}, nil
 
 }
 
+// normalizePage spells a page_url filter the way ingestion stores page_url, so equivalent
+// URLs match the same rows. An empty filter is left as is.
+func normalizePage(pageURL *string) error {
+	if *pageURL == "" {
+		return nil
+	}
+	normalized, err := event.NormalizeURL(*pageURL)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid page_url: %v", err)
+	}
+	*pageURL = normalized
+	return nil
+}
+
 func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
 	// DB connection
 	db, err := sql.Open("postgres", os.Getenv("DATABASE_URL"))
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	ctx := context.Background()
-
-	log.Printf("Connecting to Redis at: %s", getRedisAddr())
+	log.Printf("Connecting to Redis at: %s", config.RedisAddr())
 	pong, err := rdb.Ping(ctx).Result()
 	if err != nil {
 		log.Fatalf("Could not connect to Redis: %v", err)
@@ -109,14 +142,16 @@ func main() {
 	if err != nil {
 		log.Fatal(err)
 	}
-	defer db.Close()
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	metricsServer := &http.Server{Addr: ":8080", Handler: mux}
 
 	go func() {
-		http.Handle("/metrics", promhttp.Handler())
 		log.Println("Metrics server listening on :8080")
-		err := http.ListenAndServe(":8080", nil)
+		err := metricsServer.ListenAndServe()
 
-		if err != nil {
+		if err != nil && err != http.ErrServerClosed {
 			log.Fatalf("Metrics server failed: %v", err)
 		}
 	}()
@@ -127,9 +162,48 @@ func main() {
 		log.Fatal(err)
 	}
 
+	// ends the live streams once ctx is done, so the graceful stop below isn't held up by them
+	counts := newCountsHub()
+	go counts.run(ctx)
+
 	grpcServer := grpc.NewServer()
-	pb.RegisterAnalyticsServiceServer(grpcServer, &server{db: db})
+	pb.RegisterAnalyticsServiceServer(grpcServer, &server{db: db, counts: counts})
+
+	go func() {
+		log.Println("Analytics service listening on :50051")
+		if err := grpcServer.Serve(lis); err != nil {
+			log.Printf("gRPC server stopped: %v", err)
+			stop()
+		}
+	}()
+
+	<-ctx.Done()
+	log.Printf("Shutting down, draining in-flight RPCs for up to %s", shutdownTimeout)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-shutdownCtx.Done():
+		log.Println("gRPC graceful stop timed out, forcing")
+		grpcServer.Stop()
+	}
+
+	metricsServer.Shutdown(shutdownCtx)
+
+	if err := db.Close(); err != nil {
+		log.Printf("Failed to close database: %v", err)
+	}
+	if err := rdb.Close(); err != nil {
+		log.Printf("Failed to close redis: %v", err)
+	}
 
-	log.Println("Analytics service listening on :50051")
-	grpcServer.Serve(lis)
+	log.Println("Analytics stopped")
 }
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	pb "event-analytics/proto/event-analytics/proto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultPathRange = 24 * time.Hour
+	maxPathRange     = 7 * 24 * time.Hour
+	defaultPathSteps = 3
+	maxPathSteps     = 5
+	maxPathEdges     = 200
+	maxPaths         = 20
+	// events are read up to this long after the range ends, so longer sessions are cut short
+	maxPathSessionLength = 24 * time.Hour
+)
+
+// pathCount is how many visits of the requested page were followed, or preceded, by pages,
+// which start with the requested page and move away from it.
+type pathCount struct {
+	pages []string
+	count int64
+}
+
+func pathNodeID(step int, pageURL string) string {
+	return fmt.Sprintf("%d:%s", step, pageURL)
+}
+
+// GetPaths follows the sessions started in the range from every visit of a page, up to steps
+// pages forward or backward, and returns the paths as a Sankey graph. Events come from
+// click_events, grouped by the sessions the processor recorded, and repeats of a page are
+// collapsed into one visit.
+func (s *server) GetPaths(ctx context.Context, req *pb.PathsRequest) (*pb.PathsResponse, error) {
+	if req.PageUrl == "" {
+		return nil, status.Error(codes.InvalidArgument, "page_url is required")
+	}
+	if err := normalizePage(&req.PageUrl); err != nil {
+		return nil, err
+	}
+
+	direction := req.Direction
+	if direction == pb.PathDirection_PATH_DIRECTION_UNSPECIFIED {
+		direction = pb.PathDirection_PATH_DIRECTION_FORWARD
+	}
+	window := "LEAD"
+	switch direction {
+	case pb.PathDirection_PATH_DIRECTION_FORWARD:
+	case pb.PathDirection_PATH_DIRECTION_BACKWARD:
+		window = "LAG"
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown direction %v", req.Direction)
+	}
+
+	steps := int(req.Steps)
+	if steps <= 0 {
+		steps = defaultPathSteps
+	}
+	if steps > maxPathSteps {
+		return nil, status.Errorf(codes.InvalidArgument, "steps must be at most %d", maxPathSteps)
+	}
+	if req.MinWeight < 0 {
+		return nil, status.Error(codes.InvalidArgument, "min_weight must not be negative")
+	}
+	minWeight := max(req.MinWeight, 1)
+
+	start, end, err := timeRange(req.Start, req.End, defaultPathRange)
+	if err != nil {
+		return nil, err
+	}
+	if end.Sub(start) > maxPathRange {
+		return nil, status.Errorf(codes.InvalidArgument, "range must be at most %v", maxPathRange)
+	}
+
+	columns := make([]string, steps)
+	selects := make([]string, steps)
+	for k := range columns {
+		columns[k] = fmt.Sprintf("p%d", k+1)
+		selects[k] = fmt.Sprintf("%s(page_url, %d) OVER w AS p%d", window, k+1, k+1)
+	}
+
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`
+		WITH visits AS (
+			SELECT s.session_id, e.event_id, e.page_url, e.time_stamp,
+				LAG(e.page_url) OVER (PARTITION BY s.session_id ORDER BY e.time_stamp, e.event_id) AS previous
+			FROM sessions s
+			JOIN click_events e ON e.user_id = s.user_id
+				AND e.time_stamp >= s.started_at AND e.time_stamp <= s.last_seen_at
+				AND e.time_stamp >= $1 AND e.time_stamp < $4
+			WHERE s.started_at >= $1 AND s.started_at < $2
+		),
+		pages AS (
+			SELECT page_url, %[1]s
+			FROM visits
+			WHERE previous IS DISTINCT FROM page_url
+			WINDOW w AS (PARTITION BY session_id ORDER BY time_stamp, event_id)
+		)
+		SELECT %[2]s, COUNT(*)
+		FROM pages
+		WHERE page_url = $3
+		GROUP BY %[2]s
+	`, strings.Join(selects, ", "), strings.Join(columns, ", ")), start, end, req.PageUrl, end.Add(maxPathSessionLength))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var paths []pathCount
+	for rows.Next() {
+		pages := make([]sql.NullString, steps)
+		dest := make([]interface{}, 0, steps+1)
+		for k := range pages {
+			dest = append(dest, &pages[k])
+		}
+		var count int64
+		if err := rows.Scan(append(dest, &count)...); err != nil {
+			return nil, err
+		}
+		paths = append(paths, scanPath(req.PageUrl, pages, count))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	resp := pathGraph(req.PageUrl, paths, direction == pb.PathDirection_PATH_DIRECTION_BACKWARD, minWeight)
+	resp.Direction = direction
+	resp.Steps = int32(steps)
+	resp.Start = timestamppb.New(start)
+	resp.End = timestamppb.New(end)
+	return resp, nil
+}
+
+// scanPath builds the path of a result row: the requested page, then the pages of the row's
+// columns up to the first NULL, which is past the session's first or last page.
+func scanPath(pageURL string, pages []sql.NullString, count int64) pathCount {
+	path := pathCount{pages: []string{pageURL}, count: count}
+	for _, page := range pages {
+		if !page.Valid {
+			break
+		}
+		path.pages = append(path.pages, page.String)
+	}
+	return path
+}
+
+// pathGraph turns path counts into Sankey nodes and edges, keeping the heaviest edges of at
+// least minWeight, and lists the most common paths. Edges and paths always point forward in
+// time, so backward paths are reversed and their steps are negative.
+func pathGraph(pageURL string, paths []pathCount, backward bool, minWeight int64) *pb.PathsResponse {
+	step := func(i int) int {
+		if backward {
+			return -i
+		}
+		return i
+	}
+
+	resp := &pb.PathsResponse{}
+	nodes := make(map[string]*pb.PathNode)
+	edges := make(map[[2]string]int64)
+	for _, path := range paths {
+		resp.Total += path.count
+		for i, page := range path.pages {
+			id := pathNodeID(step(i), page)
+			if nodes[id] == nil {
+				nodes[id] = &pb.PathNode{Id: id, PageUrl: page, Step: int32(step(i))}
+			}
+			nodes[id].Weight += path.count
+			if i == 0 {
+				continue
+			}
+
+			edge := [2]string{pathNodeID(step(i-1), path.pages[i-1]), id}
+			if backward {
+				edge[0], edge[1] = edge[1], edge[0]
+			}
+			edges[edge] += path.count
+		}
+
+		pages := slices.Clone(path.pages)
+		if backward {
+			slices.Reverse(pages)
+		}
+		resp.Paths = append(resp.Paths, &pb.Path{Pages: pages, Count: path.count})
+	}
+
+	for edge, weight := range edges {
+		if weight >= minWeight {
+			resp.Edges = append(resp.Edges, &pb.PathEdge{Source: edge[0], Target: edge[1], Weight: weight})
+		}
+	}
+	sort.Slice(resp.Edges, func(i, j int) bool {
+		a, b := resp.Edges[i], resp.Edges[j]
+		if a.Weight != b.Weight {
+			return a.Weight > b.Weight
+		}
+		if a.Source != b.Source {
+			return a.Source < b.Source
+		}
+		return a.Target < b.Target
+	})
+	if len(resp.Edges) > maxPathEdges {
+		resp.Edges = resp.Edges[:maxPathEdges]
+	}
+
+	// only nodes an edge still reaches, and the page itself
+	kept := map[string]bool{pathNodeID(0, pageURL): true}
+	for _, edge := range resp.Edges {
+		kept[edge.Source] = true
+		kept[edge.Target] = true
+	}
+	for id, node := range nodes {
+		if kept[id] {
+			resp.Nodes = append(resp.Nodes, node)
+		}
+	}
+	sort.Slice(resp.Nodes, func(i, j int) bool {
+		a, b := resp.Nodes[i], resp.Nodes[j]
+		if a.Step != b.Step {
+			return a.Step < b.Step
+		}
+		if a.Weight != b.Weight {
+			return a.Weight > b.Weight
+		}
+		return a.PageUrl < b.PageUrl
+	})
+
+	sort.Slice(resp.Paths, func(i, j int) bool {
+		a, b := resp.Paths[i], resp.Paths[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return slices.Compare(a.Pages, b.Pages) < 0
+	})
+	if len(resp.Paths) > maxPaths {
+		resp.Paths = resp.Paths[:maxPaths]
+	}
+	return resp
+}
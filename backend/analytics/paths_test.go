@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"testing"
+
+	pb "event-analytics/proto/event-analytics/proto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestScanPath(t *testing.T) {
+	page := func(url string) sql.NullString { return sql.NullString{String: url, Valid: true} }
+
+	tests := []struct {
+		name  string
+		pages []sql.NullString
+		want  []string
+	}{
+		{"every step", []sql.NullString{page("/pricing"), page("/signup")}, []string{"/home", "/pricing", "/signup"}},
+		{"session ends early", []sql.NullString{page("/pricing"), {}, {}}, []string{"/home", "/pricing"}},
+		{"no page after it", []sql.NullString{{}, {}}, []string{"/home"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := scanPath("/home", tt.pages, 7)
+			if !slices.Equal(got.pages, tt.want) || got.count != 7 {
+				t.Errorf("scanPath = %v x%d, want %v x7", got.pages, got.count, tt.want)
+			}
+		})
+	}
+}
+
+// graph flattens a response for comparison: nodes as "id weight", edges as "source -> target
+// weight", both in response order.
+func graph(resp *pb.PathsResponse) (nodes, edges []string) {
+	for _, n := range resp.Nodes {
+		nodes = append(nodes, fmt.Sprintf("%s %d", n.Id, n.Weight))
+	}
+	for _, e := range resp.Edges {
+		edges = append(edges, fmt.Sprintf("%s -> %s %d", e.Source, e.Target, e.Weight))
+	}
+	return nodes, edges
+}
+
+func TestPathGraph(t *testing.T) {
+	tests := []struct {
+		name      string
+		pageURL   string
+		paths     []pathCount
+		backward  bool
+		minWeight int64
+		wantNodes []string
+		wantEdges []string
+		wantPaths [][]string
+	}{
+		{
+			name:    "forward",
+			pageURL: "/home",
+			paths: []pathCount{
+				{pages: []string{"/home", "/pricing", "/signup"}, count: 5},
+				{pages: []string{"/home", "/docs"}, count: 2},
+				{pages: []string{"/home"}, count: 1},
+			},
+			minWeight: 1,
+			wantNodes: []string{"0:/home 8", "1:/pricing 5", "1:/docs 2", "2:/signup 5"},
+			wantEdges: []string{"0:/home -> 1:/pricing 5", "1:/pricing -> 2:/signup 5", "0:/home -> 1:/docs 2"},
+			wantPaths: [][]string{{"/home", "/pricing", "/signup"}, {"/home", "/docs"}, {"/home"}},
+		},
+		{
+			name:    "min_weight prunes edges and the nodes only they reach",
+			pageURL: "/home",
+			paths: []pathCount{
+				{pages: []string{"/home", "/pricing", "/signup"}, count: 5},
+				{pages: []string{"/home", "/docs"}, count: 2},
+			},
+			minWeight: 3,
+			wantNodes: []string{"0:/home 7", "1:/pricing 5", "2:/signup 5"},
+			wantEdges: []string{"0:/home -> 1:/pricing 5", "1:/pricing -> 2:/signup 5"},
+			wantPaths: [][]string{{"/home", "/pricing", "/signup"}, {"/home", "/docs"}},
+		},
+		{
+			name:      "page kept without edges",
+			pageURL:   "/home",
+			paths:     []pathCount{{pages: []string{"/home", "/docs"}, count: 1}},
+			minWeight: 2,
+			wantNodes: []string{"0:/home 1"},
+			wantPaths: [][]string{{"/home", "/docs"}},
+		},
+		{
+			name:    "backward edges point forward in time",
+			pageURL: "/signup",
+			paths: []pathCount{
+				{pages: []string{"/signup", "/pricing", "/home"}, count: 4},
+				{pages: []string{"/signup", "/blog"}, count: 1},
+			},
+			backward:  true,
+			minWeight: 1,
+			wantNodes: []string{"-2:/home 4", "-1:/pricing 4", "-1:/blog 1", "0:/signup 5"},
+			wantEdges: []string{"-1:/pricing -> 0:/signup 4", "-2:/home -> -1:/pricing 4", "-1:/blog -> 0:/signup 1"},
+			wantPaths: [][]string{{"/home", "/pricing", "/signup"}, {"/blog", "/signup"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := pathGraph(tt.pageURL, tt.paths, tt.backward, tt.minWeight)
+
+			nodes, edges := graph(resp)
+			if !slices.Equal(nodes, tt.wantNodes) {
+				t.Errorf("nodes = %q, want %q", nodes, tt.wantNodes)
+			}
+			if !slices.Equal(edges, tt.wantEdges) {
+				t.Errorf("edges = %q, want %q", edges, tt.wantEdges)
+			}
+
+			var paths [][]string
+			for _, p := range resp.Paths {
+				paths = append(paths, p.Pages)
+			}
+			if !slices.EqualFunc(paths, tt.wantPaths, slices.Equal) {
+				t.Errorf("paths = %q, want %q", paths, tt.wantPaths)
+			}
+		})
+	}
+}
+
+func TestPathGraphCaps(t *testing.T) {
+	// one edge per path, the heavier the later
+	var paths []pathCount
+	for i := range maxPathEdges + 50 {
+		paths = append(paths, pathCount{pages: []string{"/home", fmt.Sprintf("/p%d", i)}, count: int64(i + 1)})
+	}
+
+	resp := pathGraph("/home", paths, false, 1)
+	if len(resp.Edges) != maxPathEdges {
+		t.Fatalf("%d edges, want %d", len(resp.Edges), maxPathEdges)
+	}
+	if first, last := resp.Edges[0], resp.Edges[maxPathEdges-1]; first.Weight != 250 || last.Weight != 51 {
+		t.Errorf("edge weights run from %d to %d, want the heaviest 250 to 51", first.Weight, last.Weight)
+	}
+	if len(resp.Nodes) != maxPathEdges+1 {
+		t.Errorf("%d nodes, want one per kept edge and the page", len(resp.Nodes))
+	}
+	if len(resp.Paths) != maxPaths || resp.Paths[0].Count != 250 {
+		t.Errorf("%d paths starting at %d, want the %d most common", len(resp.Paths), resp.Paths[0].Count, maxPaths)
+	}
+	if resp.Total != 250*251/2 {
+		t.Errorf("total = %d, want every visit counted", resp.Total)
+	}
+}
+
+func TestGetPathsLimits(t *testing.T) {
+	s := &server{}
+
+	tests := []struct {
+		name string
+		req  *pb.PathsRequest
+	}{
+		{"page required", &pb.PathsRequest{}},
+		{"too many steps", &pb.PathsRequest{PageUrl: "/home", Steps: maxPathSteps + 1}},
+		{"negative min_weight", &pb.PathsRequest{PageUrl: "/home", MinWeight: -1}},
+		{"unknown direction", &pb.PathsRequest{PageUrl: "/home", Direction: pb.PathDirection(9)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.GetPaths(context.Background(), tt.req)
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("GetPaths error = %v, want InvalidArgument", err)
+			}
+		})
+	}
+}
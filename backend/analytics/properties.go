@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	pb "event-analytics/proto/event-analytics/proto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultPropertyLimit = 50
+	maxPropertyLimit     = 1000
+	defaultPropertyRange = 24 * time.Hour
+	maxPropertyRange     = 31 * 24 * time.Hour
+)
+
+// propertyCountsQuery groups the click_events of [start, end) by a property's value. Its
+// parameters are the ones propertyCountsArgs returns, in order.
+const propertyCountsQuery = `
+	SELECT properties->>$3 AS value, COUNT(*) AS count
+	FROM click_events
+	WHERE time_stamp >= $1 AND time_stamp < $2
+	  AND properties ? $3
+	  AND properties @> $4::jsonb
+	  AND ($5 = '' OR user_id = $5)
+	  AND ($6 = '' OR page_url = $6)
+	  AND ($7 = '' OR event_type = $7)
+	GROUP BY value
+	ORDER BY count DESC, value
+	LIMIT $8
+`
+
+func propertyCountsArgs(req *pb.PropertyCountRequest, start, end time.Time, filterJSON string, limit int) []interface{} {
+	return []interface{}{start, end, req.PropertyKey, filterJSON, req.UserId, req.PageUrl, req.EventType, limit}
+}
+
+// GetPropertyCounts groups the click_events of a time range by the value of one custom
+// property, optionally narrowed to a user, page, event type and a set of property values.
+func (s *server) GetPropertyCounts(ctx context.Context, req *pb.PropertyCountRequest) (*pb.PropertyCountResponse, error) {
+	if req.PropertyKey == "" {
+		return nil, status.Error(codes.InvalidArgument, "property_key is required")
+	}
+
+	if err := normalizePage(&req.PageUrl); err != nil {
+		return nil, err
+	}
+
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = defaultPropertyLimit
+	}
+	limit = min(limit, maxPropertyLimit)
+
+	start, end, err := timeRange(req.Start, req.End, defaultPropertyRange)
+	if err != nil {
+		return nil, err
+	}
+	if end.Sub(start) > maxPropertyRange {
+		return nil, status.Errorf(codes.InvalidArgument, "range must be at most %v", maxPropertyRange)
+	}
+
+	filters := req.Filters
+	if filters == nil {
+		filters = map[string]string{}
+	}
+	filterJSON, err := json.Marshal(filters)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid filters")
+	}
+
+	rows, err := s.db.QueryContext(ctx, propertyCountsQuery,
+		propertyCountsArgs(req, start, end, string(filterJSON), limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	resp := &pb.PropertyCountResponse{
+		PropertyKey: req.PropertyKey,
+		Start:       timestamppb.New(start),
+		End:         timestamppb.New(end),
+	}
+	for rows.Next() {
+		var c pb.PropertyCount
+		if err := rows.Scan(&c.Value, &c.Count); err != nil {
+			return nil, err
+		}
+		resp.Counts = append(resp.Counts, &c)
+	}
+	return resp, rows.Err()
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	pb "event-analytics/proto/event-analytics/proto"
+)
+
+// TestPropertyCountsPlaceholders checks every use of a placeholder in the query against the
+// Go type of the argument bound to it, so renumbering the parameters can't pass a timestamp
+// where the query reads text or the other way around.
+func TestPropertyCountsPlaceholders(t *testing.T) {
+	var (
+		timeType   = reflect.TypeOf(time.Time{})
+		stringType = reflect.TypeOf("")
+		intType    = reflect.TypeOf(0)
+	)
+	// the SQL right before a placeholder, and the argument type it expects there
+	contexts := []struct {
+		before string
+		want   reflect.Type
+	}{
+		{"time_stamp >= ", timeType},
+		{"time_stamp < ", timeType},
+		{"properties->>", stringType},
+		{"properties ? ", stringType},
+		{"properties @> ", stringType},
+		{"user_id = ", stringType},
+		{"page_url = ", stringType},
+		{"event_type = ", stringType},
+		{"(", stringType}, // ($n = '' OR ...)
+		{"LIMIT ", intType},
+	}
+
+	start := time.Date(2025, 11, 10, 0, 0, 0, 0, time.UTC)
+	args := propertyCountsArgs(&pb.PropertyCountRequest{PropertyKey: "plan", UserId: "user_1", PageUrl: "/pricing", EventType: "click"},
+		start, start.Add(24*time.Hour), `{"experiment":"b"}`, 50)
+
+	used := make(map[int]bool)
+	for _, match := range regexp.MustCompile(`\$(\d+)`).FindAllStringSubmatchIndex(propertyCountsQuery, -1) {
+		n, _ := strconv.Atoi(propertyCountsQuery[match[2]:match[3]])
+		before := propertyCountsQuery[:match[0]]
+		if n < 1 || n > len(args) {
+			t.Errorf("$%d has no argument, there are %d", n, len(args))
+			continue
+		}
+		used[n] = true
+
+		found := false
+		for _, c := range contexts {
+			if strings.HasSuffix(before, c.before) {
+				found = true
+				if got := reflect.TypeOf(args[n-1]); got != c.want {
+					t.Errorf("$%d after %q is bound to a %v, want a %v", n, c.before, got, c.want)
+				}
+				break
+			}
+		}
+		if !found {
+			t.Errorf("$%d is used after %q, add its context to the test", n, before[max(0, len(before)-20):])
+		}
+	}
+
+	for n := 1; n <= len(args); n++ {
+		if !used[n] {
+			t.Errorf("argument $%d is never used", n)
+		}
+	}
+}
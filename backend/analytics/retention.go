@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"event-analytics/internal/rollup"
+	pb "event-analytics/proto/event-analytics/proto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultRetentionPeriods = 8
+	maxRetentionPeriods     = 90
+	maxRetentionCohorts     = 366
+)
+
+// retentionPeriods maps a period to its Postgres date_trunc field and width.
+var retentionPeriods = map[pb.RetentionPeriod]struct {
+	field string
+	width time.Duration
+}{
+	pb.RetentionPeriod_RETENTION_PERIOD_DAY:  {"day", 24 * time.Hour},
+	pb.RetentionPeriod_RETENTION_PERIOD_WEEK: {"week", 7 * 24 * time.Hour},
+}
+
+// truncatePeriod matches date_trunc in UTC: days start at midnight and weeks on Monday.
+func truncatePeriod(period pb.RetentionPeriod, t time.Time) time.Time {
+	day := t.UTC().Truncate(24 * time.Hour)
+	if period == pb.RetentionPeriod_RETENTION_PERIOD_WEEK {
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	}
+	return day
+}
+
+// GetRetention groups users into cohorts by the period of their first cohort event, from the
+// user_first_seen table, and counts how many of each cohort came back in every later period
+// from the clicks_per_day rollup. Cohorts older than the rollup's retention are refused since
+// their returns are partly deleted.
+func (s *server) GetRetention(ctx context.Context, req *pb.RetentionRequest) (*pb.RetentionResponse, error) {
+	period := req.Period
+	if period == pb.RetentionPeriod_RETENTION_PERIOD_UNSPECIFIED {
+		period = pb.RetentionPeriod_RETENTION_PERIOD_WEEK
+	}
+	p, ok := retentionPeriods[period]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown period %v", req.Period)
+	}
+
+	periods := int(req.Periods)
+	if periods <= 0 {
+		periods = defaultRetentionPeriods
+	}
+	if periods > maxRetentionPeriods {
+		return nil, status.Errorf(codes.InvalidArgument, "periods must be at most %d", maxRetentionPeriods)
+	}
+
+	start, end, err := timeRange(req.Start, req.End, time.Duration(periods)*p.width)
+	if err != nil {
+		return nil, err
+	}
+	start = truncatePeriod(period, start)
+	if n := end.Sub(start) / p.width; n > maxRetentionCohorts {
+		return nil, status.Errorf(codes.InvalidArgument, "range spans %d cohorts, at most %d are allowed", n, maxRetentionCohorts)
+	}
+	if now := time.Now(); rollup.Day.Expired(rollup.Day.Bucket(start), now) {
+		return nil, status.Errorf(codes.InvalidArgument, "cohorts before %s are past the %s retention",
+			rollup.Day.Cutoff(now).Format(time.DateOnly), rollup.Day.Name)
+	}
+	// the last cohort is followed for as many periods as the others
+	returnsEnd := truncatePeriod(period, end).Add(time.Duration(periods+1) * p.width)
+
+	// cohort sizes come back with a NULL period
+	rows, err := s.db.QueryContext(ctx, `
+		WITH cohorts AS (
+			SELECT user_id, date_trunc($1, first_seen AT TIME ZONE 'UTC') AS cohort
+			FROM user_first_seen
+			WHERE event_type = $2 AND first_seen >= $3 AND first_seen < $4
+		)
+		SELECT cohort, NULL::timestamp, COUNT(*)
+		FROM cohorts
+		GROUP BY cohort
+		UNION ALL
+		SELECT c.cohort, date_trunc($1, d.bucket AT TIME ZONE 'UTC') AS returned, COUNT(DISTINCT c.user_id)
+		FROM cohorts c
+		JOIN clicks_per_day d ON d.user_id = c.user_id
+		WHERE d.bucket >= $3 AND d.bucket < $5
+		  AND ($6 = '' OR d.event_type = $6)
+		  AND date_trunc($1, d.bucket AT TIME ZONE 'UTC') > c.cohort
+		GROUP BY c.cohort, returned
+	`, p.field, req.CohortEventType, start, end, returnsEnd, req.ReturnEventType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sizes := make(map[time.Time]int64)
+	returned := make(map[time.Time][]int64)
+	for rows.Next() {
+		var cohort time.Time
+		var returnedIn sql.NullTime
+		var count int64
+		if err := rows.Scan(&cohort, &returnedIn, &count); err != nil {
+			return nil, err
+		}
+		cohort = cohort.UTC()
+		if !returnedIn.Valid {
+			sizes[cohort] = count
+			continue
+		}
+
+		k := int(returnedIn.Time.UTC().Sub(cohort) / p.width)
+		if k > periods {
+			continue
+		}
+		if returned[cohort] == nil {
+			returned[cohort] = make([]int64, periods+1)
+		}
+		returned[cohort][k] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	resp := &pb.RetentionResponse{
+		Period:  period,
+		Periods: int32(periods),
+		Start:   timestamppb.New(start),
+		End:     timestamppb.New(end),
+	}
+	now := time.Now()
+	for cohort := start; cohort.Before(end); cohort = cohort.Add(p.width) {
+		users, counts := sizes[cohort], returned[cohort]
+		row := &pb.RetentionCohort{CohortStart: timestamppb.New(cohort), Users: users}
+		for k := 0; k <= periods && !cohort.Add(time.Duration(k)*p.width).After(now); k++ {
+			n := users
+			if k > 0 {
+				n = 0
+				if counts != nil {
+					n = counts[k]
+				}
+			}
+			row.Returned = append(row.Returned, n)
+			if users > 0 {
+				row.Percent = append(row.Percent, percent(n, users))
+			} else {
+				row.Percent = append(row.Percent, 0)
+			}
+		}
+		resp.Cohorts = append(resp.Cohorts, row)
+	}
+	return resp, nil
+}
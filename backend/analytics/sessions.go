@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	pb "event-analytics/proto/event-analytics/proto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultSessionLimit      = 50
+	maxSessionLimit          = 1000
+	defaultSessionStatsRange = 24 * time.Hour
+)
+
+// GetSessions lists a user's sessions from the sessions table the processor maintains,
+// newest first. Open sessions show their state as of the last processed batch.
+func (s *server) GetSessions(ctx context.Context, req *pb.SessionsRequest) (*pb.SessionsResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = defaultSessionLimit
+	}
+	limit = min(limit, maxSessionLimit)
+
+	var start, end sql.NullTime
+	if req.Start != nil {
+		start = sql.NullTime{Time: req.Start.AsTime(), Valid: true}
+	}
+	if req.End != nil {
+		end = sql.NullTime{Time: req.End.AsTime(), Valid: true}
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT session_id, started_at, ended_at, duration_ms, event_count, page_count, entry_page, exit_page
+		FROM sessions
+		WHERE user_id = $1
+		  AND ($2::timestamptz IS NULL OR started_at >= $2)
+		  AND ($3::timestamptz IS NULL OR started_at < $3)
+		ORDER BY started_at DESC
+		LIMIT $4
+	`, req.UserId, start, end, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	resp := &pb.SessionsResponse{}
+	for rows.Next() {
+		var (
+			session    = pb.Session{UserId: req.UserId}
+			startedAt  time.Time
+			endedAt    sql.NullTime
+			durationMs int64
+		)
+		if err := rows.Scan(&session.SessionId, &startedAt, &endedAt, &durationMs,
+			&session.EventCount, &session.PageCount, &session.EntryPage, &session.ExitPage); err != nil {
+			return nil, err
+		}
+
+		session.StartedAt = timestamppb.New(startedAt)
+		session.Duration = durationpb.New(time.Duration(durationMs) * time.Millisecond)
+		if endedAt.Valid {
+			session.EndedAt = timestamppb.New(endedAt.Time)
+		} else {
+			session.Active = true
+		}
+		resp.Sessions = append(resp.Sessions, &session)
+	}
+	return resp, rows.Err()
+}
+
+// GetSessionStats summarizes the sessions started in a range, for one user or everyone.
+// Duration and bounce rate only consider ended sessions, an open one is still growing.
+func (s *server) GetSessionStats(ctx context.Context, req *pb.SessionStatsRequest) (*pb.SessionStatsResponse, error) {
+	start, end, err := timeRange(req.Start, req.End, defaultSessionStatsRange)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		resp                = &pb.SessionStatsResponse{Start: timestamppb.New(start), End: timestamppb.New(end)}
+		avgMs, medianMs     sql.NullFloat64
+		avgEvents, avgPages sql.NullFloat64
+		bounceRate          sql.NullFloat64
+	)
+	err = s.db.QueryRowContext(ctx, `
+		SELECT
+			COUNT(*),
+			COUNT(*) FILTER (WHERE ended_at IS NULL),
+			AVG(duration_ms) FILTER (WHERE ended_at IS NOT NULL),
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY duration_ms) FILTER (WHERE ended_at IS NOT NULL),
+			AVG(event_count),
+			AVG(page_count),
+			AVG(CASE WHEN event_count = 1 THEN 1.0 ELSE 0.0 END) FILTER (WHERE ended_at IS NOT NULL)
+		FROM sessions
+		WHERE started_at >= $1 AND started_at < $2
+		  AND ($3 = '' OR user_id = $3)
+	`, start, end, req.UserId).Scan(&resp.SessionCount, &resp.ActiveCount, &avgMs, &medianMs, &avgEvents, &avgPages, &bounceRate)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.AvgDuration = durationpb.New(time.Duration(avgMs.Float64 * float64(time.Millisecond)))
+	resp.MedianDuration = durationpb.New(time.Duration(medianMs.Float64 * float64(time.Millisecond)))
+	resp.AvgEvents = avgEvents.Float64
+	resp.AvgPages = avgPages.Float64
+	resp.BounceRate = bounceRate.Float64
+	return resp, nil
+}
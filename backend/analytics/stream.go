@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net"
+	"sync"
+	"time"
+
+	"event-analytics/internal/live"
+	pb "event-analytics/proto/event-analytics/proto"
+
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// batches a subscriber can fall behind by before its stream is ended
+	countSubscriberBuffer = 64
+	// how long the Redis subscription may stay quiet before it is pinged
+	countPingInterval = 30 * time.Second
+)
+
+var (
+	errCountsStopping    = status.Error(codes.Unavailable, "analytics is shutting down")
+	errCountsInterrupted = status.Error(codes.Unavailable, "count updates are interrupted, reload the counts and subscribe again")
+	errCountsFellBehind  = status.Error(codes.ResourceExhausted, "subscriber fell behind, reload the counts and subscribe again")
+)
+
+// countSubscription receives the batches published for one stream. err says why batches was
+// closed, it is set before closing it.
+type countSubscription struct {
+	batches chan live.Batch
+	err     error
+}
+
+// countsHub fans the changes the processor publishes out to every SubscribeCounts stream, so
+// the service holds a single Redis subscription however many clients are connected.
+type countsHub struct {
+	mu          sync.Mutex
+	subscribers map[*countSubscription]struct{}
+	subscribed  bool // the Redis subscription is up, nothing published is being missed
+	closed      bool
+}
+
+func newCountsHub() *countsHub {
+	return &countsHub{subscribers: make(map[*countSubscription]struct{})}
+}
+
+// run relays published batches until ctx is done, then ends every stream. go-redis quietly
+// resubscribes after losing its connection, and whatever was published in between is lost,
+// so a lost connection ends every stream and clients reload the counts when they subscribe
+// again.
+func (h *countsHub) run(ctx context.Context) {
+	pubsub := rdb.Subscribe(ctx, live.Channel)
+	defer pubsub.Close()
+	defer h.endAll(errCountsStopping, true)
+
+	pinged := false
+	for {
+		msg, err := pubsub.ReceiveTimeout(ctx, countPingInterval)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() && !pinged {
+				// a quiet channel, check the connection is still there
+				if err = pubsub.Ping(ctx); err == nil {
+					pinged = true
+					continue
+				}
+			}
+			log.Printf("Count subscription lost, ending streams: %v", err)
+			h.endAll(errCountsInterrupted, false)
+			pinged = false
+
+			// the next receive reconnects and subscribes again
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+		pinged = false
+
+		switch msg := msg.(type) {
+		case *redis.Subscription:
+			h.setSubscribed()
+		case *redis.Message:
+			var batch live.Batch
+			if err := json.Unmarshal([]byte(msg.Payload), &batch); err != nil {
+				log.Printf("Failed to decode count changes: %v", err)
+				continue
+			}
+			h.broadcast(batch)
+		}
+	}
+}
+
+// subscribe returns a subscription to every batch published from now on, ended when the hub
+// stops, the Redis subscription is lost or the subscriber falls too far behind, and a func
+// to unsubscribe. It fails while the hub can't deliver every batch.
+func (h *countsHub) subscribe() (*countSubscription, func(), error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	switch {
+	case h.closed:
+		return nil, nil, errCountsStopping
+	case !h.subscribed:
+		return nil, nil, errCountsInterrupted
+	}
+	sub := &countSubscription{batches: make(chan live.Batch, countSubscriberBuffer)}
+	h.subscribers[sub] = struct{}{}
+
+	return sub, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if _, ok := h.subscribers[sub]; ok {
+			delete(h.subscribers, sub)
+			close(sub.batches)
+		}
+	}, nil
+}
+
+// broadcast never blocks on a slow subscriber, it drops it instead. Skipping a batch would
+// leave the client with wrong counts, ending the stream tells it to resubscribe and reload.
+func (h *countsHub) broadcast(batch live.Batch) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subscribers {
+		select {
+		case sub.batches <- batch:
+		default:
+			delete(h.subscribers, sub)
+			h.endLocked(sub, errCountsFellBehind)
+		}
+	}
+}
+
+func (h *countsHub) setSubscribed() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.subscribed = true
+}
+
+// endAll ends every subscription with err, until the Redis subscription is confirmed again
+// or for good when closing.
+func (h *countsHub) endAll(err error, closing bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.subscribed = false
+	h.closed = h.closed || closing
+	for sub := range h.subscribers {
+		delete(h.subscribers, sub)
+		h.endLocked(sub, err)
+	}
+}
+
+func (h *countsHub) endLocked(sub *countSubscription, err error) {
+	sub.err = err
+	close(sub.batches)
+}
+
+// SubscribeCounts streams the changes of every processed batch that touched a count matching
+// the filters. Changes are deltas, clients load the counts once and add them up.
+func (s *server) SubscribeCounts(req *pb.SubscribeCountsRequest, stream grpc.ServerStreamingServer[pb.CountUpdate]) error {
+	if err := normalizePage(&req.PageUrl); err != nil {
+		return err
+	}
+
+	sub, unsubscribe, err := s.counts.subscribe()
+	if err != nil {
+		return err
+	}
+	defer unsubscribe()
+
+	// headers tell the client it is subscribed before the first update, which may take a while
+	if err := stream.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case batch, ok := <-sub.batches:
+			if !ok {
+				return sub.err
+			}
+
+			update := &pb.CountUpdate{PublishedAt: timestamppb.New(batch.PublishedAt)}
+			for _, c := range batch.Changes {
+				if c.Matches(req.UserId, req.PageUrl, req.EventType) {
+					update.Changes = append(update.Changes, &pb.CountChange{
+						UserId:    c.UserId,
+						PageUrl:   c.PageUrl,
+						EventType: c.EventType,
+						Delta:     c.Delta,
+					})
+					update.Delta += c.Delta
+				}
+			}
+			if len(update.Changes) == 0 {
+				continue
+			}
+			if err := stream.Send(update); err != nil {
+				return err
+			}
+		}
+	}
+}
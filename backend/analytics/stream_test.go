@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"event-analytics/internal/live"
+	pb "event-analytics/proto/event-analytics/proto"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestCountsHub(t *testing.T) {
+	h := newCountsHub()
+	batch := live.Batch{Changes: []live.Change{{UserId: "user_1", Delta: 1}}}
+
+	if _, _, err := h.subscribe(); err != errCountsInterrupted {
+		t.Fatalf("subscribe before the Redis subscription = %v, want %v", err, errCountsInterrupted)
+	}
+	h.setSubscribed()
+
+	sub, unsubscribe, err := h.subscribe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unsubscribe()
+	slow, _, err := h.subscribe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for range countSubscriberBuffer {
+		h.broadcast(batch)
+		<-sub.batches
+	}
+	h.broadcast(batch)
+	if got := <-sub.batches; len(got.Changes) != 1 {
+		t.Errorf("received %+v, want the broadcast batch", got)
+	}
+	for range countSubscriberBuffer {
+		<-slow.batches
+	}
+	if _, ok := <-slow.batches; ok || slow.err != errCountsFellBehind {
+		t.Errorf("slow subscriber: open = %t, err = %v, want ended with %v", ok, slow.err, errCountsFellBehind)
+	}
+
+	// a lost Redis connection ends every stream, and refuses new ones until resubscribed
+	h.endAll(errCountsInterrupted, false)
+	if _, ok := <-sub.batches; ok || sub.err != errCountsInterrupted {
+		t.Errorf("after losing Redis: open = %t, err = %v, want ended with %v", ok, sub.err, errCountsInterrupted)
+	}
+	if _, _, err := h.subscribe(); err != errCountsInterrupted {
+		t.Errorf("subscribe while resubscribing = %v, want %v", err, errCountsInterrupted)
+	}
+	h.setSubscribed()
+	if _, _, err := h.subscribe(); err != nil {
+		t.Errorf("subscribe after resubscribing: %v", err)
+	}
+
+	h.endAll(errCountsStopping, true)
+	h.setSubscribed()
+	if _, _, err := h.subscribe(); err != errCountsStopping {
+		t.Errorf("subscribe after stopping = %v, want %v", err, errCountsStopping)
+	}
+}
+
+// TestSubscribeCountsHeaders checks what the gateway relies on before answering 200: a stream
+// that subscribed sends headers right away, one that failed ends without them.
+func TestSubscribeCountsHeaders(t *testing.T) {
+	counts := newCountsHub()
+
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer()
+	pb.RegisterAnalyticsServiceServer(grpcServer, &server{counts: counts})
+	go grpcServer.Serve(listener)
+	defer grpcServer.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := pb.NewAnalyticsServiceClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	failed := func(name string, req *pb.SubscribeCountsRequest, want codes.Code) {
+		t.Helper()
+		stream, err := client.SubscribeCounts(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if header, err := stream.Header(); err != nil || header != nil {
+			t.Errorf("%s: headers %v, %v, want none", name, header, err)
+		}
+		if _, err := stream.Recv(); status.Code(err) != want {
+			t.Errorf("%s: Recv error = %v, want %v", name, err, want)
+		}
+	}
+	failed("invalid page_url", &pb.SubscribeCountsRequest{PageUrl: "pricing"}, codes.InvalidArgument)
+	failed("not subscribed to Redis", &pb.SubscribeCountsRequest{}, codes.Unavailable)
+
+	counts.setSubscribed()
+	stream, err := client.SubscribeCounts(ctx, &pb.SubscribeCountsRequest{UserId: "user_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header, err := stream.Header(); err != nil || header == nil {
+		t.Fatalf("headers %v, %v, want them before any update", header, err)
+	}
+
+	counts.broadcast(live.Batch{Changes: []live.Change{{UserId: "user_1", PageUrl: "/home", EventType: "click", Delta: 2}}})
+	update, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if update.Delta != 2 {
+		t.Errorf("delta = %d, want 2", update.Delta)
+	}
+
+	counts.endAll(errCountsInterrupted, false)
+	if _, err := stream.Recv(); status.Code(err) != codes.Unavailable {
+		t.Errorf("Recv after losing Redis = %v, want UNAVAILABLE", err)
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"event-analytics/internal/rollup"
+	pb "event-analytics/proto/event-analytics/proto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultTimeSeriesRange = 24 * time.Hour
+	maxTimeSeriesPoints    = 10000
+)
+
+// rollupTables maps an interval to the rollup table the processor keeps for it.
+var rollupTables = map[pb.Interval]rollup.Table{
+	pb.Interval_INTERVAL_MINUTE: rollup.Minute,
+	pb.Interval_INTERVAL_HOUR:   rollup.Hour,
+	pb.Interval_INTERVAL_DAY:    rollup.Day,
+}
+
+// GetTimeSeries sums the rollup of the requested interval per bucket and fills the buckets
+// without clicks with zero, so charts get one point per bucket. Ranges reaching past the
+// rollup's retention are refused rather than zero-filled.
+func (s *server) GetTimeSeries(ctx context.Context, req *pb.TimeSeriesRequest) (*pb.TimeSeriesResponse, error) {
+	interval := req.Interval
+	if interval == pb.Interval_INTERVAL_UNSPECIFIED {
+		interval = pb.Interval_INTERVAL_HOUR
+	}
+	table, ok := rollupTables[interval]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown interval %v", req.Interval)
+	}
+
+	if err := normalizePage(&req.PageUrl); err != nil {
+		return nil, err
+	}
+
+	start, end, err := timeRange(req.Start, req.End, defaultTimeSeriesRange)
+	if err != nil {
+		return nil, err
+	}
+	start = table.Bucket(start)
+	if n := end.Sub(start) / table.Width; n > maxTimeSeriesPoints {
+		return nil, status.Errorf(codes.InvalidArgument, "range spans %d buckets, at most %d are allowed, use a wider interval", n, maxTimeSeriesPoints)
+	}
+	// pruned buckets would read as zero, which looks like no traffic
+	if now := time.Now(); table.Expired(start, now) {
+		return nil, status.Errorf(codes.InvalidArgument, "buckets before %s are past the %s retention, use a wider interval",
+			table.Cutoff(now).Format(time.RFC3339), table.Name)
+	}
+
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT bucket, SUM(click_count)
+		FROM %s
+		WHERE bucket >= $1 AND bucket < $2
+		  AND ($3 = '' OR page_url = $3)
+		  AND ($4 = '' OR user_id = $4)
+		  AND ($5 = '' OR event_type = $5)
+		GROUP BY bucket
+	`, table.Name), start, end, req.PageUrl, req.UserId, req.EventType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[time.Time]int64)
+	for rows.Next() {
+		var bucket time.Time
+		var count int64
+		if err := rows.Scan(&bucket, &count); err != nil {
+			return nil, err
+		}
+		counts[bucket.UTC()] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	resp := &pb.TimeSeriesResponse{
+		Interval: interval,
+		Start:    timestamppb.New(start),
+		End:      timestamppb.New(end),
+	}
+	for bucket := start; bucket.Before(end); bucket = bucket.Add(table.Width) {
+		count := counts[bucket]
+		resp.Total += count
+		resp.Points = append(resp.Points, &pb.TimeSeriesPoint{Bucket: timestamppb.New(bucket), Count: count})
+	}
+	return resp, nil
+}
+
+// timeRange resolves an optional [start, end) pair: end defaults to now and start to
+// fallback before end. Both are returned in UTC.
+func timeRange(start, end *timestamppb.Timestamp, fallback time.Duration) (time.Time, time.Time, error) {
+	to := time.Now().UTC()
+	if end != nil {
+		to = end.AsTime()
+	}
+	from := to.Add(-fallback)
+	if start != nil {
+		from = start.AsTime()
+	}
+	if !to.After(from) {
+		return time.Time{}, time.Time{}, status.Error(codes.InvalidArgument, "end must be after start")
+	}
+	return from, to, nil
+}
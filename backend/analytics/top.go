@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"time"
+
+	"event-analytics/internal/leaderboard"
+	"event-analytics/internal/rollup"
+	pb "event-analytics/proto/event-analytics/proto"
+
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultTopWindow = time.Hour
+	defaultTopLimit  = 10
+	maxTopLimit      = 100
+)
+
+// TopPages returns the most clicked pages over the last window.
+func (s *server) TopPages(ctx context.Context, req *pb.TopRequest) (*pb.TopResponse, error) {
+	return s.top(ctx, req, leaderboard.Pages, "page_url")
+}
+
+// TopUsers returns the users with the most clicks over the last window.
+func (s *server) TopUsers(ctx context.Context, req *pb.TopRequest) (*pb.TopResponse, error) {
+	return s.top(ctx, req, leaderboard.Users, "user_id")
+}
+
+// top answers windows within the real-time horizon from the per-minute Redis sorted sets the
+// processor maintains, and longer windows, or any window while Redis is down, from the
+// finest Postgres rollup that still covers it.
+func (s *server) top(ctx context.Context, req *pb.TopRequest, dimension, column string) (*pb.TopResponse, error) {
+	window := defaultTopWindow
+	if req.Window != nil {
+		if err := req.Window.CheckValid(); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid window: %v", err)
+		}
+		window = req.Window.AsDuration()
+	}
+	if window <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "window must be positive")
+	}
+
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = defaultTopLimit
+	}
+	limit = min(limit, maxTopLimit)
+
+	now := time.Now()
+	since := now.Add(-window)
+
+	if leaderboard.InHorizon(since, now) {
+		entries, err := topFromRedis(ctx, dimension, req.EventType, since, now, limit)
+		if err == nil {
+			return &pb.TopResponse{
+				Entries: entries,
+				Since:   timestamppb.New(since.UTC().Truncate(leaderboard.BucketWidth)),
+				Source:  "redis",
+			}, nil
+		}
+		log.Printf("Leaderboard unavailable in Redis, falling back to Postgres: %v", err)
+	}
+
+	table := rollup.Covering(since, now)
+	since = table.Bucket(since)
+
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT %[1]s, SUM(click_count) AS count
+		FROM %[2]s
+		WHERE bucket >= $1
+		  AND ($2 = '' OR event_type = $2)
+		GROUP BY %[1]s
+		ORDER BY count DESC, %[1]s
+		LIMIT $3
+	`, column, table.Name), since, req.EventType, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	resp := &pb.TopResponse{Since: timestamppb.New(since), Source: "postgres"}
+	for rows.Next() {
+		var entry pb.TopEntry
+		if err := rows.Scan(&entry.Key, &entry.Count); err != nil {
+			return nil, err
+		}
+		resp.Entries = append(resp.Entries, &entry)
+	}
+	return resp, rows.Err()
+}
+
+// topFromRedis unions the window's per-minute sorted sets into a scratch key and reads the
+// highest scores, all in one transaction.
+func topFromRedis(ctx context.Context, dimension, eventType string, since, now time.Time, limit int) ([]*pb.TopEntry, error) {
+	scratch := fmt.Sprintf("top:scratch:%s:%016x", dimension, rand.Uint64())
+
+	pipe := rdb.TxPipeline()
+	pipe.ZUnionStore(ctx, scratch, &redis.ZStore{Keys: leaderboard.Keys(dimension, eventType, since, now)})
+	ranked := pipe.ZRevRangeWithScores(ctx, scratch, 0, int64(limit-1))
+	pipe.Del(ctx, scratch)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	entries := make([]*pb.TopEntry, 0, len(ranked.Val()))
+	for _, z := range ranked.Val() {
+		entries = append(entries, &pb.TopEntry{Key: z.Member.(string), Count: int64(z.Score)})
+	}
+	return entries, nil
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"event-analytics/internal/uniques"
+	pb "event-analytics/proto/event-analytics/proto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const defaultUniquesRange = 24 * time.Hour
+
+// GetUniqueUsers counts distinct users of a page, or of the whole site, by PFCOUNTing every
+// HyperLogLog covering the range at once, which counts the union of their users.
+func (s *server) GetUniqueUsers(ctx context.Context, req *pb.UniqueUsersRequest) (*pb.UniqueUsersResponse, error) {
+	if err := normalizePage(&req.PageUrl); err != nil {
+		return nil, err
+	}
+
+	start, end, err := timeRange(req.Start, req.End, defaultUniquesRange)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, start, end, err := uniques.Keys(req.PageUrl, start, end, time.Now())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	count, err := rdb.PFCount(ctx, keys...).Result()
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "can't count unique users: %v", err)
+	}
+
+	margin := int64(math.Ceil(2 * uniques.StandardError * float64(count)))
+	return &pb.UniqueUsersResponse{
+		Count:         count,
+		StandardError: uniques.StandardError,
+		LowerBound:    max(count-margin, 0),
+		UpperBound:    count + margin,
+		Start:         timestamppb.New(start),
+		End:           timestamppb.New(end),
+	}, nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	pb "event-analytics/proto/event-analytics/proto"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// funnelHandler serves GET /analytics/funnel?step=&step=&window=&start=&end=
+//
+// Each step is a page_url, optionally followed by |event_type, e.g. step=/pricing or
+// step=|signup. Steps are taken in the order given.
+func funnelHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	req := &pb.FunnelRequest{}
+	for _, step := range query["step"] {
+		pageURL, eventType, _ := strings.Cut(step, "|")
+		req.Steps = append(req.Steps, &pb.FunnelStep{PageUrl: pageURL, EventType: eventType})
+	}
+
+	if window := query.Get("window"); window != "" {
+		d, err := time.ParseDuration(window)
+		if err != nil || d <= 0 {
+			http.Error(w, "window must be a positive duration such as 30m or 24h", http.StatusBadRequest)
+			return
+		}
+		req.Window = durationpb.New(d)
+	}
+
+	var ok bool
+	if req.Start, ok = parseTimeParam(w, query.Get("start"), "start"); !ok {
+		return
+	}
+	if req.End, ok = parseTimeParam(w, query.Get("end"), "end"); !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	resp, err := analyticsClient.GetFunnel(ctx, req)
+	if err != nil {
+		writeGRPCError(w, err, "failed to get funnel")
+		return
+	}
+
+	steps := make([]map[string]interface{}, 0, len(resp.Steps))
+	for _, s := range resp.Steps {
+		step := map[string]interface{}{
+			"page_url":                          s.Step.PageUrl,
+			"event_type":                        s.Step.EventType,
+			"users":                             s.Users,
+			"conversion_percent":                s.ConversionPercent,
+			"step_conversion_percent":           s.StepConversionPercent,
+			"drop_off_percent":                  s.DropOffPercent,
+			"median_seconds_from_previous_step": nil,
+		}
+		if s.MedianTimeFromPrevious != nil {
+			step["median_seconds_from_previous_step"] = s.MedianTimeFromPrevious.AsDuration().Seconds()
+		}
+		steps = append(steps, step)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"window": resp.Window.AsDuration().String(),
+		"start":  resp.Start.AsTime(),
+		"end":    resp.End.AsTime(),
+		"steps":  steps,
+	})
+}
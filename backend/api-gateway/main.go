@@ -5,8 +5,13 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
+	"event-analytics/internal/config"
 	pb "event-analytics/proto/event-analytics/proto"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -14,7 +19,12 @@ import (
 	"google.golang.org/grpc/credentials/insecure"
 )
 
-var analyticsClient pb.AnalyticsServiceClient
+var (
+	analyticsConn   *grpc.ClientConn
+	analyticsClient pb.AnalyticsServiceClient
+	analyticsAddr   = config.GetEnv("ANALYTICS_SERVICE_URL", "analytics:50051")
+	shutdownTimeout = config.GetDuration("SHUTDOWN_TIMEOUT", 25*time.Second)
+)
 
 func initGRPCclient() {
 	var conn *grpc.ClientConn
@@ -22,7 +32,7 @@ func initGRPCclient() {
 
 	// Retry connection with backoff
 	for i := 0; i < 5; i++ {
-		conn, err = grpc.NewClient("analytics:50051", grpc.WithTransportCredentials(insecure.NewCredentials()))
+		conn, err = grpc.NewClient(analyticsAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
 		if err == nil {
 			break
 		}
@@ -34,6 +44,7 @@ func initGRPCclient() {
 		log.Fatalf("FATAL: could not connect to grpc server after 5 retries: %v", err)
 	}
 
+	analyticsConn = conn
 	analyticsClient = pb.NewAnalyticsServiceClient(conn)
 
 	log.Print("STATUS SUCCESSFULL: GPRC server connected with client, working smooothly")
@@ -46,8 +57,9 @@ func analyticsHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req struct {
-		UserID  string `json:"user_id"`
-		PageUrl string `json:"page_url"`
+		UserID    string `json:"user_id"`
+		PageUrl   string `json:"page_url"`
+		EventType string `json:"event_type"`
 	}
 
 	err := json.NewDecoder(r.Body).Decode(&req)
@@ -57,38 +69,150 @@ func analyticsHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// omitting one of them asks for the total of the other
+	if req.UserID == "" && req.PageUrl == "" {
+		http.Error(w, "user_id or page_url is required", http.StatusBadRequest)
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
 	resp, err := analyticsClient.GetEventCount(ctx, &pb.EventCountRequest{
-		UserId:  req.UserID,
-		PageUrl: req.PageUrl,
+		UserId:    req.UserID,
+		PageUrl:   req.PageUrl,
+		EventType: req.EventType,
 	})
 
 	if err != nil {
-		log.Printf("grpc called failed %v", err)
-		http.Error(w, "failed to get analytics", http.StatusNotFound)
+		writeGRPCError(w, err, "failed to get analytics")
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"user_id":  resp.UserId,
-		"page_url": resp.PageUrl,
-		"count":    resp.Count,
+		"user_id":    resp.UserId,
+		"page_url":   resp.PageUrl,
+		"event_type": resp.EventType,
+		"count":      resp.Count,
 	})
 
 }
 
+// propertiesHandler groups event counts by a custom property, e.g.
+// GET /analytics/properties?key=plan&page_url=/pricing&filter=experiment_variant:b&start=&end=
+func propertiesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	req := &pb.PropertyCountRequest{
+		PropertyKey: query.Get("key"),
+		UserId:      query.Get("user_id"),
+		PageUrl:     query.Get("page_url"),
+		EventType:   query.Get("event_type"),
+		Filters:     map[string]string{},
+	}
+	if req.PropertyKey == "" {
+		http.Error(w, "key is required", http.StatusBadRequest)
+		return
+	}
+
+	for _, filter := range query["filter"] {
+		key, value, ok := strings.Cut(filter, ":")
+		if !ok || key == "" {
+			http.Error(w, "filter must look like key:value", http.StatusBadRequest)
+			return
+		}
+		req.Filters[key] = value
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		req.Limit = int32(n)
+	}
+
+	var ok bool
+	if req.Start, ok = parseTimeParam(w, query.Get("start"), "start"); !ok {
+		return
+	}
+	if req.End, ok = parseTimeParam(w, query.Get("end"), "end"); !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	resp, err := analyticsClient.GetPropertyCounts(ctx, req)
+	if err != nil {
+		writeGRPCError(w, err, "failed to get property counts")
+		return
+	}
+
+	counts := make([]map[string]interface{}, 0, len(resp.Counts))
+	for _, c := range resp.Counts {
+		counts = append(counts, map[string]interface{}{
+			"value": c.Value,
+			"count": c.Count,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"property_key": resp.PropertyKey,
+		"start":        resp.Start.AsTime(),
+		"end":          resp.End.AsTime(),
+		"counts":       counts,
+	})
+}
+
 func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
 	initGRPCclient()
 
-	http.HandleFunc("/analytics/events", analyticsHandler)
-	http.Handle("/metrics", promhttp.Handler())
+	mux := http.NewServeMux()
+	mux.HandleFunc("/analytics/events", analyticsHandler)
+	mux.HandleFunc("/analytics/properties", propertiesHandler)
+	mux.HandleFunc("/analytics/timeseries", timeseriesHandler)
+	mux.HandleFunc("/analytics/top/pages", topHandler(analyticsClient.TopPages, "page_url"))
+	mux.HandleFunc("/analytics/top/users", topHandler(analyticsClient.TopUsers, "user_id"))
+	mux.HandleFunc("/analytics/uniques", uniquesHandler)
+	mux.HandleFunc("/analytics/sessions", sessionsHandler)
+	mux.HandleFunc("/analytics/sessions/stats", sessionStatsHandler)
+	mux.HandleFunc("/analytics/funnel", funnelHandler)
+	mux.HandleFunc("/analytics/retention", retentionHandler)
+	mux.HandleFunc("/analytics/paths", pathsHandler)
+	mux.HandleFunc("/analytics/stream", streamHandler)
+	mux.Handle("/metrics", promhttp.Handler())
+	server := &http.Server{Addr: ":8081", Handler: mux}
+	server.RegisterOnShutdown(func() { close(streamsClosing) })
+
+	go func() {
+		log.Println("API Gateway listening on :8081")
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatal(err)
+		}
+	}()
+
+	<-ctx.Done()
+	log.Printf("Shutting down, draining in-flight requests for up to %s", shutdownTimeout)
 
-	log.Println("API Gateway listening on :8081")
-	if err := http.ListenAndServe(":8081", nil); err != nil {
-		log.Fatal(err)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("HTTP shutdown incomplete: %v", err)
 	}
+	analyticsConn.Close()
+
+	log.Println("API Gateway stopped")
 }
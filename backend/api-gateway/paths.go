@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	pb "event-analytics/proto/event-analytics/proto"
+)
+
+var pathDirections = map[string]pb.PathDirection{
+	"forward":  pb.PathDirection_PATH_DIRECTION_FORWARD,
+	"backward": pb.PathDirection_PATH_DIRECTION_BACKWARD,
+}
+
+// pathsHandler serves GET /analytics/paths?page_url=&direction=&steps=&min_weight=&start=&end=
+func pathsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	req := &pb.PathsRequest{PageUrl: query.Get("page_url")}
+	if req.PageUrl == "" {
+		http.Error(w, "page_url is required", http.StatusBadRequest)
+		return
+	}
+
+	if direction := query.Get("direction"); direction != "" {
+		var ok bool
+		if req.Direction, ok = pathDirections[direction]; !ok {
+			http.Error(w, "direction must be forward or backward", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if steps := query.Get("steps"); steps != "" {
+		n, err := strconv.Atoi(steps)
+		if err != nil || n <= 0 {
+			http.Error(w, "steps must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		req.Steps = int32(n)
+	}
+
+	if minWeight := query.Get("min_weight"); minWeight != "" {
+		n, err := strconv.ParseInt(minWeight, 10, 64)
+		if err != nil || n <= 0 {
+			http.Error(w, "min_weight must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		req.MinWeight = n
+	}
+
+	var ok bool
+	if req.Start, ok = parseTimeParam(w, query.Get("start"), "start"); !ok {
+		return
+	}
+	if req.End, ok = parseTimeParam(w, query.Get("end"), "end"); !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	resp, err := analyticsClient.GetPaths(ctx, req)
+	if err != nil {
+		writeGRPCError(w, err, "failed to get paths")
+		return
+	}
+
+	nodes := make([]map[string]interface{}, 0, len(resp.Nodes))
+	for _, n := range resp.Nodes {
+		nodes = append(nodes, map[string]interface{}{
+			"id":       n.Id,
+			"page_url": n.PageUrl,
+			"step":     n.Step,
+			"weight":   n.Weight,
+		})
+	}
+
+	edges := make([]map[string]interface{}, 0, len(resp.Edges))
+	for _, e := range resp.Edges {
+		edges = append(edges, map[string]interface{}{
+			"source": e.Source,
+			"target": e.Target,
+			"weight": e.Weight,
+		})
+	}
+
+	paths := make([]map[string]interface{}, 0, len(resp.Paths))
+	for _, p := range resp.Paths {
+		paths = append(paths, map[string]interface{}{
+			"pages": p.Pages,
+			"count": p.Count,
+		})
+	}
+
+	direction := "forward"
+	if resp.Direction == pb.PathDirection_PATH_DIRECTION_BACKWARD {
+		direction = "backward"
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"page_url":  req.PageUrl,
+		"direction": direction,
+		"steps":     resp.Steps,
+		"start":     resp.Start.AsTime(),
+		"end":       resp.End.AsTime(),
+		"total":     resp.Total,
+		"nodes":     nodes,
+		"edges":     edges,
+		"paths":     paths,
+	})
+}
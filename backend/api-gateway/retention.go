@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	pb "event-analytics/proto/event-analytics/proto"
+)
+
+var retentionPeriods = map[string]pb.RetentionPeriod{
+	"day":  pb.RetentionPeriod_RETENTION_PERIOD_DAY,
+	"week": pb.RetentionPeriod_RETENTION_PERIOD_WEEK,
+}
+
+// retentionHandler serves GET /analytics/retention?period=&cohort_event_type=&return_event_type=&periods=&start=&end=
+//
+// The response is a matrix with a row per cohort and a column per period since it, periods
+// that haven't started yet are null.
+func retentionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	req := &pb.RetentionRequest{
+		CohortEventType: query.Get("cohort_event_type"),
+		ReturnEventType: query.Get("return_event_type"),
+	}
+
+	if period := query.Get("period"); period != "" {
+		var ok bool
+		if req.Period, ok = retentionPeriods[period]; !ok {
+			http.Error(w, "period must be day or week", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if periods := query.Get("periods"); periods != "" {
+		n, err := strconv.Atoi(periods)
+		if err != nil || n <= 0 {
+			http.Error(w, "periods must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		req.Periods = int32(n)
+	}
+
+	var ok bool
+	if req.Start, ok = parseTimeParam(w, query.Get("start"), "start"); !ok {
+		return
+	}
+	if req.End, ok = parseTimeParam(w, query.Get("end"), "end"); !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	resp, err := analyticsClient.GetRetention(ctx, req)
+	if err != nil {
+		writeGRPCError(w, err, "failed to get retention")
+		return
+	}
+
+	period := "week"
+	if resp.Period == pb.RetentionPeriod_RETENTION_PERIOD_DAY {
+		period = "day"
+	}
+
+	columns := make([]string, resp.Periods+1)
+	for k := range columns {
+		columns[k] = fmt.Sprintf("%s %d", period, k)
+	}
+
+	rows := make([]map[string]interface{}, 0, len(resp.Cohorts))
+	for _, c := range resp.Cohorts {
+		returned := make([]interface{}, len(columns))
+		percent := make([]interface{}, len(columns))
+		for k := range c.Returned {
+			returned[k] = c.Returned[k]
+			percent[k] = c.Percent[k]
+		}
+		rows = append(rows, map[string]interface{}{
+			"cohort":   c.CohortStart.AsTime().Format(time.DateOnly),
+			"users":    c.Users,
+			"returned": returned,
+			"percent":  percent,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"period":            period,
+		"cohort_event_type": req.CohortEventType,
+		"return_event_type": req.ReturnEventType,
+		"start":             resp.Start.AsTime(),
+		"end":               resp.End.AsTime(),
+		"columns":           columns,
+		"rows":              rows,
+	})
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	pb "event-analytics/proto/event-analytics/proto"
+)
+
+// sessionsHandler serves GET /analytics/sessions?user_id=&start=&end=&limit=
+func sessionsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	req := &pb.SessionsRequest{UserId: query.Get("user_id")}
+	if req.UserId == "" {
+		http.Error(w, "user_id is required", http.StatusBadRequest)
+		return
+	}
+
+	var ok bool
+	if req.Start, ok = parseTimeParam(w, query.Get("start"), "start"); !ok {
+		return
+	}
+	if req.End, ok = parseTimeParam(w, query.Get("end"), "end"); !ok {
+		return
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		req.Limit = int32(n)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	resp, err := analyticsClient.GetSessions(ctx, req)
+	if err != nil {
+		writeGRPCError(w, err, "failed to get sessions")
+		return
+	}
+
+	sessions := make([]map[string]interface{}, 0, len(resp.Sessions))
+	for _, s := range resp.Sessions {
+		session := map[string]interface{}{
+			"session_id":       s.SessionId,
+			"started_at":       s.StartedAt.AsTime(),
+			"ended_at":         nil,
+			"duration_seconds": s.Duration.AsDuration().Seconds(),
+			"event_count":      s.EventCount,
+			"page_count":       s.PageCount,
+			"entry_page":       s.EntryPage,
+			"exit_page":        s.ExitPage,
+			"active":           s.Active,
+		}
+		if s.EndedAt != nil {
+			session["ended_at"] = s.EndedAt.AsTime()
+		}
+		sessions = append(sessions, session)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"user_id":  req.UserId,
+		"sessions": sessions,
+	})
+}
+
+// sessionStatsHandler serves GET /analytics/sessions/stats?user_id=&start=&end=
+func sessionStatsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	req := &pb.SessionStatsRequest{UserId: query.Get("user_id")}
+
+	var ok bool
+	if req.Start, ok = parseTimeParam(w, query.Get("start"), "start"); !ok {
+		return
+	}
+	if req.End, ok = parseTimeParam(w, query.Get("end"), "end"); !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	resp, err := analyticsClient.GetSessionStats(ctx, req)
+	if err != nil {
+		writeGRPCError(w, err, "failed to get session stats")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"user_id":                 req.UserId,
+		"start":                   resp.Start.AsTime(),
+		"end":                     resp.End.AsTime(),
+		"session_count":           resp.SessionCount,
+		"active_count":            resp.ActiveCount,
+		"avg_duration_seconds":    resp.AvgDuration.AsDuration().Seconds(),
+		"median_duration_seconds": resp.MedianDuration.AsDuration().Seconds(),
+		"avg_events":              resp.AvgEvents,
+		"avg_pages":               resp.AvgPages,
+		"bounce_rate":             resp.BounceRate,
+	})
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"event-analytics/internal/config"
+	pb "event-analytics/proto/event-analytics/proto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	// comments sent on an idle stream so proxies don't time it out
+	streamKeepAlive = config.GetDuration("STREAM_KEEPALIVE", 15*time.Second)
+
+	// closed on shutdown, open streams never finish on their own and would hold up draining
+	streamsClosing = make(chan struct{})
+)
+
+// streamHandler serves GET /analytics/stream?user_id=&page_url=&event_type= as Server-Sent
+// Events, relaying a SubscribeCounts stream. Each processed batch that changed a matching
+// count is a "counts" event. Clients load the counts first and add up the deltas, and
+// reload them after reconnecting since batches published while disconnected are missed.
+func streamHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	query := r.URL.Query()
+	req := &pb.SubscribeCountsRequest{
+		UserId:    query.Get("user_id"),
+		PageUrl:   query.Get("page_url"),
+		EventType: query.Get("event_type"),
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	// a server stream only reports a failure on receive: analytics sends headers once it has
+	// subscribed, and fails without them, so wait for them before answering 200
+	stream, err := analyticsClient.SubscribeCounts(ctx, req)
+	if err == nil {
+		var header metadata.MD
+		if header, err = stream.Header(); err == nil && header == nil {
+			_, err = stream.Recv()
+		}
+	}
+	if err != nil {
+		writeGRPCError(w, err, "failed to subscribe to counts")
+		return
+	}
+
+	updates := make(chan *pb.CountUpdate)
+	errs := make(chan error, 1)
+	go func() {
+		for {
+			update, err := stream.Recv()
+			if err != nil {
+				errs <- err
+				return
+			}
+			select {
+			case updates <- update:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "retry: 3000\n\n")
+	flusher.Flush()
+
+	ticker := time.NewTicker(streamKeepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-streamsClosing:
+			return
+		case err := <-errs:
+			// the client reconnects on its own
+			if status.Code(err) != codes.Canceled {
+				log.Printf("count stream ended: %v", err)
+			}
+			return
+		case <-ticker.C:
+			fmt.Fprint(w, ": keepalive\n\n")
+		case update := <-updates:
+			changes := make([]map[string]interface{}, 0, len(update.Changes))
+			for _, c := range update.Changes {
+				changes = append(changes, map[string]interface{}{
+					"user_id":    c.UserId,
+					"page_url":   c.PageUrl,
+					"event_type": c.EventType,
+					"delta":      c.Delta,
+				})
+			}
+			payload, err := json.Marshal(map[string]interface{}{
+				"delta":        update.Delta,
+				"changes":      changes,
+				"published_at": update.PublishedAt.AsTime(),
+			})
+			if err != nil {
+				log.Printf("failed to encode count update: %v", err)
+				continue
+			}
+			fmt.Fprintf(w, "event: counts\ndata: %s\n\n", payload)
+		}
+		flusher.Flush()
+	}
+}
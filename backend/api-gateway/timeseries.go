@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	pb "event-analytics/proto/event-analytics/proto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var intervals = map[string]pb.Interval{
+	"minute": pb.Interval_INTERVAL_MINUTE,
+	"hour":   pb.Interval_INTERVAL_HOUR,
+	"day":    pb.Interval_INTERVAL_DAY,
+}
+
+// timeseriesHandler serves GET /analytics/timeseries?page_url=&user_id=&event_type=&start=&end=&interval=
+func timeseriesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	req := &pb.TimeSeriesRequest{
+		PageUrl:   query.Get("page_url"),
+		UserId:    query.Get("user_id"),
+		EventType: query.Get("event_type"),
+	}
+
+	if interval := query.Get("interval"); interval != "" {
+		var ok bool
+		if req.Interval, ok = intervals[interval]; !ok {
+			http.Error(w, "interval must be minute, hour or day", http.StatusBadRequest)
+			return
+		}
+	}
+
+	var ok bool
+	if req.Start, ok = parseTimeParam(w, query.Get("start"), "start"); !ok {
+		return
+	}
+	if req.End, ok = parseTimeParam(w, query.Get("end"), "end"); !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	resp, err := analyticsClient.GetTimeSeries(ctx, req)
+	if err != nil {
+		writeGRPCError(w, err, "failed to get time series")
+		return
+	}
+
+	points := make([]map[string]interface{}, 0, len(resp.Points))
+	for _, p := range resp.Points {
+		points = append(points, map[string]interface{}{
+			"bucket": p.Bucket.AsTime(),
+			"count":  p.Count,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"interval": intervalName(resp.Interval),
+		"start":    resp.Start.AsTime(),
+		"end":      resp.End.AsTime(),
+		"total":    resp.Total,
+		"points":   points,
+	})
+}
+
+// parseTimeParam parses an optional RFC 3339 query parameter, writing a 400 when malformed.
+func parseTimeParam(w http.ResponseWriter, value, name string) (*timestamppb.Timestamp, bool) {
+	if value == "" {
+		return nil, true
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		http.Error(w, name+" must be an RFC 3339 timestamp", http.StatusBadRequest)
+		return nil, false
+	}
+	return timestamppb.New(t), true
+}
+
+func intervalName(interval pb.Interval) string {
+	for name, i := range intervals {
+		if i == interval {
+			return name
+		}
+	}
+	return interval.String()
+}
+
+// writeGRPCError turns an analytics error into the matching HTTP status. Invalid arguments
+// are passed through so clients can see what to fix, anything else is logged and hidden.
+func writeGRPCError(w http.ResponseWriter, err error, message string) {
+	st := status.Convert(err)
+	switch st.Code() {
+	case codes.InvalidArgument:
+		http.Error(w, st.Message(), http.StatusBadRequest)
+	case codes.NotFound:
+		http.Error(w, st.Message(), http.StatusNotFound)
+	case codes.Unavailable, codes.DeadlineExceeded:
+		log.Printf("grpc called failed %v", err)
+		http.Error(w, message, http.StatusServiceUnavailable)
+	default:
+		log.Printf("grpc called failed %v", err)
+		http.Error(w, message, http.StatusInternalServerError)
+	}
+}
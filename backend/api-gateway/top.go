@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	pb "event-analytics/proto/event-analytics/proto"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+type topFunc func(ctx context.Context, req *pb.TopRequest, opts ...grpc.CallOption) (*pb.TopResponse, error)
+
+// topHandler serves GET /analytics/top/pages and /analytics/top/users?window=&limit=&event_type=
+func topHandler(top topFunc, keyName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		query := r.URL.Query()
+		req := &pb.TopRequest{EventType: query.Get("event_type")}
+
+		if window := query.Get("window"); window != "" {
+			d, err := time.ParseDuration(window)
+			if err != nil || d <= 0 {
+				http.Error(w, "window must be a positive duration such as 15m or 24h", http.StatusBadRequest)
+				return
+			}
+			req.Window = durationpb.New(d)
+		}
+
+		if limit := query.Get("limit"); limit != "" {
+			n, err := strconv.Atoi(limit)
+			if err != nil || n <= 0 {
+				http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+				return
+			}
+			req.Limit = int32(n)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+
+		resp, err := top(ctx, req)
+		if err != nil {
+			writeGRPCError(w, err, "failed to get leaderboard")
+			return
+		}
+
+		entries := make([]map[string]interface{}, 0, len(resp.Entries))
+		for _, e := range resp.Entries {
+			entries = append(entries, map[string]interface{}{
+				keyName: e.Key,
+				"count": e.Count,
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"since":   resp.Since.AsTime(),
+			"source":  resp.Source,
+			"entries": entries,
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	pb "event-analytics/proto/event-analytics/proto"
+)
+
+// uniquesHandler serves GET /analytics/uniques?page_url=&start=&end=
+func uniquesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	req := &pb.UniqueUsersRequest{PageUrl: query.Get("page_url")}
+
+	var ok bool
+	if req.Start, ok = parseTimeParam(w, query.Get("start"), "start"); !ok {
+		return
+	}
+	if req.End, ok = parseTimeParam(w, query.Get("end"), "end"); !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	resp, err := analyticsClient.GetUniqueUsers(ctx, req)
+	if err != nil {
+		writeGRPCError(w, err, "failed to get unique users")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"page_url":       req.PageUrl,
+		"count":          resp.Count,
+		"standard_error": resp.StandardError,
+		"lower_bound":    resp.LowerBound,
+		"upper_bound":    resp.UpperBound,
+		"start":          resp.Start.AsTime(),
+		"end":            resp.End.AsTime(),
+	})
+}
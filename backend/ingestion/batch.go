@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"event-analytics/internal/event"
+)
+
+const (
+	maxBatchEvents = 500
+	maxBatchBytes  = 5 << 20 // 5 MiB
+)
+
+type BatchResult struct {
+	Index   int                `json:"index"`
+	EventId string             `json:"event_id,omitempty"`
+	Status  string             `json:"status"`
+	Error   string             `json:"error,omitempty"`
+	Fields  []event.FieldError `json:"fields,omitempty"`
+}
+
+type BatchResponse struct {
+	Accepted int           `json:"accepted"`
+	Rejected int           `json:"rejected"`
+	Results  []BatchResult `json:"results"`
+}
+
+// decodeBatch splits the request body into raw events. A body starting with '['
+// is treated as a JSON array, anything else as newline-delimited JSON.
+func decodeBatch(body io.Reader) ([]json.RawMessage, error) {
+	reader := bufio.NewReader(body)
+
+	first, err := peekNonSpace(reader)
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var items []json.RawMessage
+	if first == '[' {
+		err = json.NewDecoder(reader).Decode(&items)
+		return items, err
+	}
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), maxBatchBytes)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		items = append(items, json.RawMessage(bytes.Clone(line)))
+	}
+	return items, scanner.Err()
+}
+
+func peekNonSpace(reader *bufio.Reader) (byte, error) {
+	for {
+		b, err := reader.Peek(1)
+		if err != nil {
+			return 0, err
+		}
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			reader.ReadByte()
+		default:
+			return b[0], nil
+		}
+	}
+}
+
+// batchHandler accepts a JSON array or NDJSON body of click events, validates each one
+// and delivers the accepted ones together like /ingest delivers one: 201 once Kafka acked
+// them in sync mode, 202 once buffered in async mode, and the same 429 and 503 errors.
+func (s *IngestService) batchHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	items, err := decodeBatch(http.MaxBytesReader(w, r.Body, maxBatchBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "batch body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "invalid batch body", http.StatusBadRequest)
+		return
+	}
+
+	if len(items) == 0 {
+		http.Error(w, "batch is empty", http.StatusBadRequest)
+		return
+	}
+	if len(items) > maxBatchEvents {
+		http.Error(w, "too many events in batch", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	response := BatchResponse{Results: make([]BatchResult, len(items))}
+	accepted := make([]event.ClickEvent, 0, len(items))
+
+	for i, item := range items {
+		result := &response.Results[i]
+		result.Index = i
+
+		var clickEvent event.ClickEvent
+		if err := json.Unmarshal(item, &clickEvent); err != nil {
+			result.Status = "rejected"
+			result.Error = "invalid json"
+			continue
+		}
+		verr := s.prepare(&clickEvent)
+		result.EventId = clickEvent.EventId
+		if verr != nil {
+			result.Status = "rejected"
+			result.Error = "validation failed"
+			result.Fields = verr.Fields
+			continue
+		}
+
+		accepted = append(accepted, clickEvent)
+		result.Status = "accepted"
+	}
+
+	if err := s.deliver(r.Context(), accepted...); err != nil {
+		writeDeliveryError(w, err)
+		return
+	}
+
+	response.Accepted = len(accepted)
+	response.Rejected = len(items) - len(accepted)
+
+	code := http.StatusOK // nothing to deliver
+	if len(accepted) > 0 {
+		code = s.deliveredStatus()
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(response)
+}
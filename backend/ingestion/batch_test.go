@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"event-analytics/internal/event"
+
+	"github.com/segmentio/kafka-go"
+)
+
+func TestBatchHandlerAsync(t *testing.T) {
+	registry, err := event.LoadRegistry("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	valid := `{"user_id":"user_1","event_type":"click","page_url":"/home"}`
+	invalid := `{"user_id":"user_1","event_type":"click"}`
+
+	tests := []struct {
+		name         string
+		bufferSize   int
+		buffered     int
+		closed       bool
+		body         string
+		wantCode     int
+		wantAccepted int
+		wantRetry    string
+	}{
+		{"buffers the accepted events", 10, 0, false, valid + "\n" + invalid + "\n" + valid, http.StatusAccepted, 2, ""},
+		{"fills the buffer exactly", 3, 1, false, valid + "\n" + valid, http.StatusAccepted, 2, ""},
+		{"whole batch refused when it doesn't fit", 3, 2, false, valid + "\n" + valid, http.StatusTooManyRequests, 0, "1"},
+		{"nothing to deliver", 10, 0, false, invalid, http.StatusOK, 0, ""},
+		{"shutting down", 10, 0, true, valid, http.StatusServiceUnavailable, 0, "1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			channel := make(chan []event.ClickEvent, tt.bufferSize)
+			service := &IngestService{
+				EventChannel: channel,
+				BufferSize:   tt.bufferSize,
+				Registry:     registry,
+				Mode:         deliveryAsync,
+			}
+			service.buffered.Store(int64(tt.buffered))
+			if tt.closed {
+				service.closeEvents()
+			}
+
+			w := httptest.NewRecorder()
+			service.batchHandler(w, httptest.NewRequest(http.MethodPost, "/ingest/batch", strings.NewReader(tt.body)))
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantCode, w.Body)
+			}
+			if got := w.Header().Get("Retry-After"); got != tt.wantRetry {
+				t.Errorf("Retry-After = %q, want %q", got, tt.wantRetry)
+			}
+
+			if w.Code == http.StatusAccepted {
+				var response BatchResponse
+				if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+					t.Fatal(err)
+				}
+				if response.Accepted != tt.wantAccepted {
+					t.Errorf("accepted = %d, want %d", response.Accepted, tt.wantAccepted)
+				}
+			}
+
+			// accepted events are buffered together, behind whatever was there
+			if tt.closed {
+				return
+			}
+			var got []event.ClickEvent
+			if len(channel) > 0 {
+				got = <-channel
+			}
+			if len(got) != tt.wantAccepted {
+				t.Errorf("buffered %d events in one send, want %d", len(got), tt.wantAccepted)
+			}
+			if want := int64(tt.buffered + tt.wantAccepted); service.buffered.Load() != want {
+				t.Errorf("buffered count = %d, want %d", service.buffered.Load(), want)
+			}
+		})
+	}
+}
+
+func TestBatchHandlerSyncBehindSpool(t *testing.T) {
+	registry, err := event.LoadRegistry("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	spool, err := OpenSpool(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer spool.Close()
+	if err := spool.Append(kafka.Message{Key: []byte("user_1"), Value: []byte(`{}`)}); err != nil {
+		t.Fatal(err)
+	}
+
+	service := &IngestService{Spool: spool, Registry: registry, Mode: deliverySync}
+
+	// the backlog is replayed first, Kafka is never written to
+	w := httptest.NewRecorder()
+	body := `{"user_id":"user_1","event_type":"click","page_url":"/home"}`
+	service.batchHandler(w, httptest.NewRequest(http.MethodPost, "/ingest/batch", strings.NewReader(body)))
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusServiceUnavailable, w.Body)
+	}
+	if got := w.Header().Get("Retry-After"); got != "5" {
+		t.Errorf("Retry-After = %q, want %q", got, "5")
+	}
+}
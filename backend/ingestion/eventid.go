@@ -0,0 +1,83 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"event-analytics/internal/event"
+)
+
+var eventIDs uuidV7Generator
+
+// uuidV7Generator produces RFC 9562 UUIDv7 strings. The first 48 bits are the
+// millisecond timestamp so IDs sort by time; within one millisecond a 12-bit
+// counter in rand_a keeps IDs from the same instance strictly increasing.
+type uuidV7Generator struct {
+	mu sync.Mutex
+	// lastMs is the latest event time seen, idMs the time in the last ID, which runs ahead of
+	// lastMs after the counter borrowed the next millisecond
+	lastMs int64
+	idMs   int64
+	seq    uint16
+}
+
+// New returns a UUIDv7 for an event that happened at t. Events dated after now get now,
+// so a client clock running ahead can't push the counter past the present and leave every
+// later event looking back-dated.
+func (g *uuidV7Generator) New(t, now time.Time) string {
+	ms := min(t.UnixMilli(), now.UnixMilli())
+
+	var seq uint16
+	g.mu.Lock()
+	switch {
+	case ms < g.lastMs:
+		// a back-dated event: seq stays 0 and the random bits keep it unique
+	case ms > g.idMs:
+		g.lastMs, g.idMs, g.seq = ms, ms, 0
+	default:
+		// the same millisecond, or one the counter already borrowed
+		g.lastMs = ms
+		g.seq++
+		if g.seq > 0x0fff { // counter exhausted, borrow the next millisecond
+			g.idMs++
+			g.seq = 0
+		}
+		ms, seq = g.idMs, g.seq
+	}
+	g.mu.Unlock()
+
+	var b [16]byte
+	rand.Read(b[8:])
+
+	b[0] = byte(ms >> 40)
+	b[1] = byte(ms >> 32)
+	b[2] = byte(ms >> 24)
+	b[3] = byte(ms >> 16)
+	b[4] = byte(ms >> 8)
+	b[5] = byte(ms)
+	b[6] = 0x70 | byte(seq>>8) // version 7
+	b[7] = byte(seq)
+	b[8] = 0x80 | (b[8] & 0x3f) // RFC 9562 variant
+
+	var out [36]byte
+	hex.Encode(out[0:8], b[0:4])
+	out[8] = '-'
+	hex.Encode(out[9:13], b[4:6])
+	out[13] = '-'
+	hex.Encode(out[14:18], b[6:8])
+	out[18] = '-'
+	hex.Encode(out[19:23], b[8:10])
+	out[23] = '-'
+	hex.Encode(out[24:], b[10:])
+	return string(out[:])
+}
+
+// assignEventID fills in a time-sortable ID when the client did not send one. Client IDs
+// are kept as-is since they are the idempotency key for retries.
+func assignEventID(clickEvent *event.ClickEvent, now time.Time) {
+	if clickEvent.EventId == "" {
+		clickEvent.EventId = eventIDs.New(clickEvent.TimeStamp, now)
+	}
+}
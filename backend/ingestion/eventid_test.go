@@ -0,0 +1,207 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"event-analytics/internal/event"
+)
+
+// parseUUIDv7 returns the millisecond timestamp and counter of an ID built by uuidV7Generator.
+func parseUUIDv7(t *testing.T, id string) (int64, uint16) {
+	t.Helper()
+
+	hexDigits := strings.ReplaceAll(id, "-", "")
+	if len(id) != 36 || len(hexDigits) != 32 {
+		t.Fatalf("malformed uuid %q", id)
+	}
+	if hexDigits[12] != '7' {
+		t.Fatalf("uuid %q is not version 7", id)
+	}
+	if !strings.ContainsRune("89ab", rune(hexDigits[16])) {
+		t.Fatalf("uuid %q does not have the RFC 9562 variant", id)
+	}
+
+	ms, err := strconv.ParseInt(hexDigits[:12], 16, 64)
+	if err != nil {
+		t.Fatalf("uuid %q: %v", id, err)
+	}
+	seq, err := strconv.ParseUint(hexDigits[13:16], 16, 16)
+	if err != nil {
+		t.Fatalf("uuid %q: %v", id, err)
+	}
+	return ms, uint16(seq)
+}
+
+// generatorState is the part of uuidV7Generator New reads and writes.
+type generatorState struct {
+	lastMs, idMs int64
+	seq          uint16
+}
+
+func TestUUIDv7GeneratorNew(t *testing.T) {
+	// the server clock, no later than any event below except the future-dated one
+	now := time.UnixMilli(1002)
+
+	tests := []struct {
+		name    string
+		state   generatorState
+		at      int64
+		wantMs  int64
+		wantSeq uint16
+		// generator state afterwards
+		next generatorState
+	}{
+		{"first id", generatorState{}, 1000, 1000, 0, generatorState{lastMs: 1000, idMs: 1000}},
+		{
+			"later millisecond resets the counter",
+			generatorState{lastMs: 1000, idMs: 1000, seq: 7}, 1001,
+			1001, 0, generatorState{lastMs: 1001, idMs: 1001},
+		},
+		{
+			"same millisecond increments the counter",
+			generatorState{lastMs: 1000, idMs: 1000, seq: 7}, 1000,
+			1000, 8, generatorState{lastMs: 1000, idMs: 1000, seq: 8},
+		},
+		{
+			"last counter value",
+			generatorState{lastMs: 1000, idMs: 1000, seq: 0x0ffe}, 1000,
+			1000, 0x0fff, generatorState{lastMs: 1000, idMs: 1000, seq: 0x0fff},
+		},
+		{
+			"counter overflow borrows the next millisecond",
+			generatorState{lastMs: 1000, idMs: 1000, seq: 0x0fff}, 1000,
+			1001, 0, generatorState{lastMs: 1000, idMs: 1001},
+		},
+		{
+			"same millisecond after borrowing stays on the borrowed one",
+			generatorState{lastMs: 1000, idMs: 1001}, 1000,
+			1001, 1, generatorState{lastMs: 1000, idMs: 1001, seq: 1},
+		},
+		{
+			"clock reaching the borrowed millisecond continues its counter",
+			generatorState{lastMs: 1000, idMs: 1001, seq: 3}, 1001,
+			1001, 4, generatorState{lastMs: 1001, idMs: 1001, seq: 4},
+		},
+		{
+			"clock passing the borrowed millisecond resets the counter",
+			generatorState{lastMs: 1000, idMs: 1001, seq: 3}, 1002,
+			1002, 0, generatorState{lastMs: 1002, idMs: 1002},
+		},
+		{
+			"future-dated event is clamped to now",
+			generatorState{lastMs: 1000, idMs: 1000, seq: 7}, 1000 + 5*60*1000,
+			1002, 0, generatorState{lastMs: 1002, idMs: 1002},
+		},
+		{
+			"back-dated event keeps its own time",
+			generatorState{lastMs: 1000, idMs: 1000, seq: 7}, 900,
+			900, 0, generatorState{lastMs: 1000, idMs: 1000, seq: 7},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &uuidV7Generator{lastMs: tt.state.lastMs, idMs: tt.state.idMs, seq: tt.state.seq}
+
+			ms, seq := parseUUIDv7(t, g.New(time.UnixMilli(tt.at), now))
+			if ms != tt.wantMs || seq != tt.wantSeq {
+				t.Errorf("New(%d) = ms %d seq %#x, want ms %d seq %#x", tt.at, ms, seq, tt.wantMs, tt.wantSeq)
+			}
+			if g.lastMs != tt.next.lastMs || g.idMs != tt.next.idMs || g.seq != tt.next.seq {
+				t.Errorf("state = lastMs %d idMs %d seq %#x, want lastMs %d idMs %d seq %#x",
+					g.lastMs, g.idMs, g.seq, tt.next.lastMs, tt.next.idMs, tt.next.seq)
+			}
+		})
+	}
+}
+
+func TestUUIDv7GeneratorSortsWithinMillisecond(t *testing.T) {
+	g := &uuidV7Generator{}
+	at := time.UnixMilli(1_700_000_000_000)
+
+	// more than the 4096 counter values, so some IDs spill into the next millisecond
+	previous := g.New(at, at)
+	for i := 0; i < 5000; i++ {
+		id := g.New(at, at)
+		if id <= previous {
+			t.Fatalf("id %d %q does not sort after %q", i, id, previous)
+		}
+		previous = id
+	}
+}
+
+func TestUUIDv7GeneratorAfterFutureEvent(t *testing.T) {
+	g := &uuidV7Generator{}
+	now := time.UnixMilli(1_700_000_000_000)
+
+	// a client clock 5 minutes ahead, within max_future, must not make later events back-dated
+	previous := g.New(now.Add(5*time.Minute), now)
+	for i := 0; i < 3; i++ {
+		id := g.New(now, now)
+		if ms, seq := parseUUIDv7(t, id); ms != now.UnixMilli() || seq != uint16(i+1) {
+			t.Errorf("event %d: ms %d seq %d, want ms %d seq %d", i, ms, seq, now.UnixMilli(), i+1)
+		}
+		if id <= previous {
+			t.Errorf("event %d: %q does not sort after %q", i, id, previous)
+		}
+		previous = id
+	}
+}
+
+func TestPrepareAssignsIDsToValidEventsOnly(t *testing.T) {
+	registry, err := event.LoadRegistry("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := &IngestService{Registry: registry}
+
+	tests := []struct {
+		name      string
+		event     event.ClickEvent
+		wantValid bool
+		wantID    string
+	}{
+		{
+			name:      "valid event without an id",
+			event:     event.ClickEvent{UserId: "user_1", EventType: "click", PageUrl: "/home"},
+			wantValid: true,
+		},
+		{
+			name:      "client id is kept",
+			event:     event.ClickEvent{EventId: "client_1", UserId: "user_1", EventType: "click", PageUrl: "/home"},
+			wantValid: true,
+			wantID:    "client_1",
+		},
+		{
+			name:  "too far in the future",
+			event: event.ClickEvent{UserId: "user_1", EventType: "click", PageUrl: "/home", TimeStamp: time.Now().Add(time.Hour)},
+		},
+		{
+			name:  "missing user_id",
+			event: event.ClickEvent{EventType: "click", PageUrl: "/home"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clickEvent := tt.event
+			verr := service.prepare(&clickEvent)
+			if (verr == nil) != tt.wantValid {
+				t.Fatalf("prepare() = %v, want valid %v", verr, tt.wantValid)
+			}
+			switch {
+			case tt.wantID != "":
+				if clickEvent.EventId != tt.wantID {
+					t.Errorf("event_id = %q, want %q", clickEvent.EventId, tt.wantID)
+				}
+			case tt.wantValid:
+				parseUUIDv7(t, clickEvent.EventId)
+			case clickEvent.EventId != "":
+				t.Errorf("rejected event was given id %q", clickEvent.EventId)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sort"
+
+	"event-analytics/internal/event"
+	pb "event-analytics/proto/event-analytics/proto"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	maxStreamEvents     = 10000 // per IngestStream, keeps the response within the message size limit
+	maxDetailRejections = 100   // listed in the detail of a failed stream, keeps it within the trailer limit
+)
+
+// grpcIngestServer exposes the ingestion service over gRPC. It shares prepare and deliver
+// with the HTTP handlers so both APIs validate and publish the same way.
+type grpcIngestServer struct {
+	pb.UnimplementedIngestServiceServer
+	service *IngestService
+}
+
+func (g *grpcIngestServer) Ingest(ctx context.Context, req *pb.IngestRequest) (*pb.IngestResponse, error) {
+	if req.Event == nil {
+		return nil, status.Error(codes.InvalidArgument, "event is required")
+	}
+
+	clickEvent := event.FromProto(req.Event)
+	if verr := g.service.prepare(&clickEvent); verr != nil {
+		return nil, validationStatus(verr)
+	}
+
+	if err := g.service.deliver(ctx, clickEvent); err != nil {
+		return nil, deliveryStatus(err).Err()
+	}
+	return &pb.IngestResponse{
+		EventId:   clickEvent.EventId,
+		Delivered: g.service.Mode == deliverySync,
+	}, nil
+}
+
+// deliveryStatus maps a failed deliver to the gRPC status matching the HTTP answer.
+func deliveryStatus(err error) *status.Status {
+	switch {
+	case errors.Is(err, errBufferFull):
+		return status.New(codes.ResourceExhausted, "ingestion buffer is full, retry later")
+	case errors.Is(err, errKafkaUnavailable):
+		return status.New(codes.Unavailable, "event not accepted, kafka unavailable")
+	case errors.Is(err, errSpoolBacklog):
+		return status.New(codes.Unavailable, "event not accepted, spooled events are still being replayed")
+	case errors.Is(err, errShuttingDown):
+		return status.New(codes.Unavailable, "ingestion is shutting down, retry later")
+	default:
+		return status.New(codes.Internal, "failed to serialize event")
+	}
+}
+
+// IngestStream validates every streamed event and delivers the accepted ones in batches of
+// maxBatchEvents, the same way /ingest/batch delivers a request. A stream longer than
+// maxStreamEvents fails once the events before the limit are delivered.
+func (g *grpcIngestServer) IngestStream(stream pb.IngestService_IngestStreamServer) error {
+	resp := &pb.IngestStreamResponse{}
+	events := make([]event.ClickEvent, 0, maxBatchEvents)
+	indexes := make([]int64, 0, maxBatchEvents)
+
+	flush := func() error {
+		if err := g.service.deliver(stream.Context(), events...); err != nil {
+			return partialStreamStatus(deliveryStatus(err), resp)
+		}
+		resp.Accepted += int64(len(events))
+		for i, clickEvent := range events {
+			resp.AcceptedEvents = append(resp.AcceptedEvents, &pb.AcceptedEvent{Index: indexes[i], EventId: clickEvent.EventId})
+		}
+		// deliver keeps the slice in async mode
+		events = make([]event.ClickEvent, 0, maxBatchEvents)
+		indexes = indexes[:0]
+		return nil
+	}
+
+	for index := int64(0); ; index++ {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if index == maxStreamEvents {
+			if err := flush(); err != nil {
+				return err
+			}
+			st := status.Newf(codes.InvalidArgument, "stream is limited to %d events, send the rest in a new one", maxStreamEvents)
+			return partialStreamStatus(st, resp)
+		}
+
+		if req.Event == nil {
+			resp.Rejected++
+			resp.Rejections = append(resp.Rejections, &pb.IngestRejection{
+				Index:      index,
+				Violations: []*pb.FieldViolation{{Field: "event", Message: "is required"}},
+			})
+			continue
+		}
+
+		clickEvent := event.FromProto(req.Event)
+		if verr := g.service.prepare(&clickEvent); verr != nil {
+			rejection := &pb.IngestRejection{Index: index, EventId: clickEvent.EventId}
+			for _, f := range verr.Fields {
+				rejection.Violations = append(rejection.Violations, &pb.FieldViolation{Field: f.Field, Message: f.Message})
+			}
+			resp.Rejected++
+			resp.Rejections = append(resp.Rejections, rejection)
+			continue
+		}
+
+		events = append(events, clickEvent)
+		indexes = append(indexes, index)
+		if len(events) == maxBatchEvents {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := flush(); err != nil {
+		return err
+	}
+	resp.Delivered = g.service.Mode == deliverySync
+	return stream.SendAndClose(resp)
+}
+
+// partialStreamStatus fails a stream with st and a summary of the response so far as a
+// detail. Batches flushed before the failure were delivered: the detail keeps the counts, the
+// last delivered event in accepted_events and the first maxDetailRejections rejections after
+// it. The client resends the events after it that are not listed; rejected ones it resends
+// are rejected again.
+func partialStreamStatus(st *status.Status, resp *pb.IngestStreamResponse) error {
+	summary := &pb.IngestStreamResponse{Accepted: resp.Accepted, Rejected: resp.Rejected}
+
+	after := int64(-1)
+	if n := len(resp.AcceptedEvents); n > 0 {
+		summary.AcceptedEvents = resp.AcceptedEvents[n-1:]
+		after = resp.AcceptedEvents[n-1].Index
+	}
+	first := sort.Search(len(resp.Rejections), func(i int) bool { return resp.Rejections[i].Index > after })
+	rejections := resp.Rejections[first:]
+	summary.Rejections = rejections[:min(len(rejections), maxDetailRejections)]
+
+	if withDetails, err := st.WithDetails(summary); err == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
+
+// validationStatus maps a registry failure to INVALID_ARGUMENT with a BadRequest detail.
+func validationStatus(verr *event.ValidationError) error {
+	st := status.New(codes.InvalidArgument, verr.Error())
+
+	badRequest := &errdetails.BadRequest{}
+	for _, f := range verr.Fields {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       f.Field,
+			Description: f.Message,
+		})
+	}
+
+	if withDetails, err := st.WithDetails(badRequest); err == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"testing"
+
+	pb "event-analytics/proto/event-analytics/proto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPartialStreamStatus(t *testing.T) {
+	resp := &pb.IngestStreamResponse{}
+	// events 0-999 delivered except every tenth, rejected; 1000-1999 rejected since then
+	for index := int64(0); index < 2000; index++ {
+		if index < 1000 && index%10 != 0 {
+			resp.Accepted++
+			resp.AcceptedEvents = append(resp.AcceptedEvents, &pb.AcceptedEvent{Index: index})
+			continue
+		}
+		resp.Rejected++
+		resp.Rejections = append(resp.Rejections, &pb.IngestRejection{Index: index})
+	}
+
+	err := partialStreamStatus(status.New(codes.Unavailable, "kafka unavailable"), resp)
+	st := status.Convert(err)
+	if st.Code() != codes.Unavailable || len(st.Details()) != 1 {
+		t.Fatalf("status = %v with %d details, want UNAVAILABLE with the summary", st.Code(), len(st.Details()))
+	}
+	summary, ok := st.Details()[0].(*pb.IngestStreamResponse)
+	if !ok {
+		t.Fatalf("detail is a %T, want an IngestStreamResponse", st.Details()[0])
+	}
+
+	if summary.Accepted != 900 || summary.Rejected != 1100 {
+		t.Errorf("counts = %d accepted, %d rejected, want the stream's 900 and 1100", summary.Accepted, summary.Rejected)
+	}
+	if len(summary.AcceptedEvents) != 1 || summary.AcceptedEvents[0].Index != 999 {
+		t.Errorf("accepted_events = %v, want only the last delivered event", summary.AcceptedEvents)
+	}
+	if n := len(summary.Rejections); n != maxDetailRejections || summary.Rejections[0].Index != 1000 || summary.Rejections[n-1].Index != 1000+maxDetailRejections-1 {
+		t.Errorf("%d rejections from %d, want the first %d after the last delivered event", n, summary.Rejections[0].Index, maxDetailRejections)
+	}
+
+	// nothing delivered yet: every rejection counts as after it
+	err = partialStreamStatus(status.New(codes.ResourceExhausted, "buffer full"), &pb.IngestStreamResponse{
+		Rejected:   1,
+		Rejections: []*pb.IngestRejection{{Index: 0}},
+	})
+	summary = status.Convert(err).Details()[0].(*pb.IngestStreamResponse)
+	if len(summary.AcceptedEvents) != 0 || len(summary.Rejections) != 1 {
+		t.Errorf("summary = %v, want no accepted events and the one rejection", summary)
+	}
+}
@@ -3,51 +3,209 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
-	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
 
+	"event-analytics/internal/config"
+	"event-analytics/internal/event"
+	pb "event-analytics/proto/event-analytics/proto"
+
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/segmentio/kafka-go"
+	"google.golang.org/grpc"
 )
 
-type ClickEvent struct {
-	EventId   string    `json:"event_id"`
-	UserId    string    `json:"user_id"`
-	EventType string    `json:"event_type"`
-	PageUrl   string    `json:"page_url"`
-	TimeStamp time.Time `json:"time_stamp"`
-}
-
 var (
-	kafkaBroker = getEnv("KAFKA_BROKER", "localhost:9092")
-	kafkaTopic  = getEnv("KAFKA_TOPIC", "clicks")
+	kafkaBroker = config.GetEnv("KAFKA_BROKER", "localhost:9092")
+	kafkaTopic  = config.GetEnv("KAFKA_TOPIC", "clicks")
+	spoolDir    = config.GetEnv("SPOOL_DIR", "spool")
+	// empty means the built-in event_types.json
+	registryPath = config.GetEnv("EVENT_REGISTRY_PATH", "")
+	// async: 202 once buffered, 429 when the buffer is full
+	// sync: 201 once Kafka acked the event, 503 when it could not
+	deliveryMode = config.GetEnv("INGEST_DELIVERY_MODE", deliveryAsync)
+	// events buffered in async mode, at least maxBatchEvents so a full batch fits
+	bufferSize = config.GetInt("INGEST_BUFFER_SIZE", 1000)
+	// payload encoding on the clicks topic, "json" until every processor can read protobuf,
+	// then switch to "protobuf"
+	wireFormat = config.GetEnv("KAFKA_WIRE_FORMAT", string(event.FormatJSON))
+	grpcAddr   = config.GetEnv("INGEST_GRPC_ADDR", ":50052")
+	// must stay below the pod's terminationGracePeriodSeconds
+	shutdownTimeout = config.GetDuration("SHUTDOWN_TIMEOUT", 25*time.Second)
 )
 
-func getEnv(key, fallback string) string {
-	var Key_Check = os.Getenv(key)
-	if Key_Check != "" {
-		return Key_Check
-	} else {
-		return fallback
-	}
-}
+const (
+	deliveryAsync = "async"
+	deliverySync  = "sync"
+)
 
 type IngestService struct {
-	EventChannel chan<- ClickEvent
+	// EventChannel carries the events of each request together, its capacity must be at
+	// least BufferSize
+	EventChannel chan<- []event.ClickEvent
+	BufferSize   int
+	Spool        *Spool
+	Registry     *event.EventRegistry
+	Mode         string
+	Format       event.Format
+
+	// events sent on EventChannel and not yet taken by the publish loop
+	buffered atomic.Int64
+
+	// closeMu guards EventChannel against sends after closeEvents closed it
+	closeMu sync.RWMutex
+	closed  bool
 }
 
 func createProducer(kafkaBroker, kafkaTopic string) *kafka.Writer {
 	return kafka.NewWriter(kafka.WriterConfig{
 		Brokers: []string{kafkaBroker},
 		Topic:   kafkaTopic,
+		// messages are keyed by user_id, hashing keeps each user's events on one partition
+		// and therefore in order, which sessionization relies on
+		Balancer: &kafka.Hash{},
+		// the default 1s batch timeout would add up to a second to every sync request
+		BatchTimeout: 10 * time.Millisecond,
 	})
 }
 
 var producer = createProducer(kafkaBroker, kafkaTopic)
 
+var (
+	errBufferFull       = errors.New("ingestion buffer is full")
+	errKafkaUnavailable = errors.New("kafka unavailable")
+	errSpoolBacklog     = errors.New("spooled events are still being replayed")
+	errShuttingDown     = errors.New("ingestion is shutting down")
+)
+
+// prepare fills in the server-side defaults and validates the event against the registry.
+// HTTP and gRPC ingestion both go through it. Only valid events are given an ID, so rejected
+// ones never advance the ID generator.
+func (s *IngestService) prepare(clickEvent *event.ClickEvent) *event.ValidationError {
+	now := time.Now()
+	if clickEvent.TimeStamp.IsZero() {
+		clickEvent.TimeStamp = now
+	}
+
+	if verr := s.Registry.Validate(clickEvent, now); verr != nil {
+		return verr
+	}
+	assignEventID(clickEvent, now)
+	return nil
+}
+
+// deliver hands validated events to Kafka according to the delivery mode, all or none of
+// them. In sync mode it returns once the broker acked them in one write, in async mode once
+// they are buffered behind any spooled backlog. Single events, batches and streams all go
+// through it.
+func (s *IngestService) deliver(ctx context.Context, events ...event.ClickEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	if s.Mode == deliverySync {
+		// writing past the backlog would let a user's new events overtake their spooled ones
+		if s.Spool.Depth() > 0 {
+			return errSpoolBacklog
+		}
+
+		messages := make([]kafka.Message, 0, len(events))
+		for _, clickEvent := range events {
+			msg, err := event.Encode(clickEvent, s.Format)
+			if err != nil {
+				return err
+			}
+			messages = append(messages, msg)
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, time.Second*10)
+		defer cancel()
+
+		if err := producer.WriteMessages(ctx, messages...); err != nil {
+			log.Printf("Not able to write message in kafka stream: %v", err)
+			return errKafkaUnavailable
+		}
+		return nil
+	}
+
+	s.closeMu.RLock()
+	defer s.closeMu.RUnlock()
+
+	if s.closed {
+		return errShuttingDown
+	}
+
+	n := int64(len(events))
+	if s.buffered.Add(n) > int64(s.BufferSize) {
+		s.buffered.Add(-n)
+		return errBufferFull
+	}
+	// never blocks, every buffered slice holds at least one event
+	s.EventChannel <- events
+	return nil
+}
+
+// closeEvents stops async delivery so the publish loop can drain what is buffered and exit.
+func (s *IngestService) closeEvents() {
+	s.closeMu.Lock()
+	defer s.closeMu.Unlock()
+
+	if !s.closed {
+		s.closed = true
+		close(s.EventChannel)
+	}
+}
+
+// publishLoop writes buffered events to Kafka, one write per request, spooling them when
+// Kafka is unavailable. It returns once the channel is closed and drained.
+func (s *IngestService) publishLoop(batches <-chan []event.ClickEvent) {
+	for events := range batches {
+		s.buffered.Add(-int64(len(events)))
+
+		messages := make([]kafka.Message, 0, len(events))
+		for _, clickEvent := range events {
+			msg, err := event.Encode(clickEvent, s.Format)
+			if err != nil {
+				log.Printf("Error while serializing data: %v", err)
+				continue
+			}
+			messages = append(messages, msg)
+		}
+		if len(messages) == 0 {
+			continue
+		}
+
+		// while there is a backlog keep appending behind it, the drainer replays in order
+		if s.Spool.Depth() > 0 {
+			if err := s.Spool.Append(messages...); err != nil {
+				log.Printf("ERROR: %d events lost, can't write to spool: %v", len(messages), err)
+			}
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+
+		err := producer.WriteMessages(ctx, messages...)
+
+		cancel()
+
+		if err != nil {
+			log.Printf("Not able to write message in kafka stream, spooling to disk: %v", err)
+			if err := s.Spool.Append(messages...); err != nil {
+				log.Printf("ERROR: %d events lost, can't write to spool: %v", len(messages), err)
+			}
+		}
+	}
+}
+
 // response , request
 func (s *IngestService) ingestHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -55,7 +213,7 @@ func (s *IngestService) ingestHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var PerClickEvent ClickEvent
+	var PerClickEvent event.ClickEvent
 	err := json.NewDecoder(r.Body).Decode(&PerClickEvent)
 
 	if err != nil {
@@ -63,58 +221,198 @@ func (s *IngestService) ingestHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if PerClickEvent.TimeStamp.IsZero() {
-		PerClickEvent.TimeStamp = time.Now()
+	if verr := s.prepare(&PerClickEvent); verr != nil {
+		writeValidationError(w, verr)
+		return
+	}
+
+	if err := s.deliver(r.Context(), PerClickEvent); err != nil {
+		writeDeliveryError(w, err)
+		return
+	}
+	writeEvent(w, s.deliveredStatus(), PerClickEvent)
+}
+
+// deliveredStatus is the HTTP status of a successful delivery: 201 once Kafka acked the
+// events, 202 once they are buffered.
+func (s *IngestService) deliveredStatus() int {
+	if s.Mode == deliverySync {
+		return http.StatusCreated
 	}
-	s.EventChannel <- PerClickEvent
+	return http.StatusAccepted
+}
 
+// writeDeliveryError answers a failed deliver the same way for single events and batches.
+func writeDeliveryError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, errBufferFull):
+		w.Header().Set("Retry-After", "1")
+		http.Error(w, "ingestion buffer is full, retry later", http.StatusTooManyRequests)
+	case errors.Is(err, errKafkaUnavailable):
+		w.Header().Set("Retry-After", "5")
+		http.Error(w, "event not accepted, kafka unavailable", http.StatusServiceUnavailable)
+	case errors.Is(err, errSpoolBacklog):
+		w.Header().Set("Retry-After", "5")
+		http.Error(w, "event not accepted, spooled events are still being replayed", http.StatusServiceUnavailable)
+	case errors.Is(err, errShuttingDown):
+		w.Header().Set("Retry-After", "1")
+		http.Error(w, "ingestion is shutting down, retry later", http.StatusServiceUnavailable)
+	default:
+		http.Error(w, "failed to serialize event", http.StatusInternalServerError)
+	}
+}
+
+func writeEvent(w http.ResponseWriter, status int, clickEvent event.ClickEvent) {
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(PerClickEvent)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(clickEvent)
+}
 
+func writeValidationError(w http.ResponseWriter, verr *event.ValidationError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	json.NewEncoder(w).Encode(struct {
+		Error  string             `json:"error"`
+		Fields []event.FieldError `json:"fields"`
+	}{
+		Error:  "validation failed",
+		Fields: verr.Fields,
+	})
 }
 
 func main() {
+	if deliveryMode != deliveryAsync && deliveryMode != deliverySync {
+		log.Fatalf("FATAL: INGEST_DELIVERY_MODE must be %q or %q, got %q", deliveryAsync, deliverySync, deliveryMode)
+	}
 
-	eventChannel := make(chan ClickEvent, 100)
-	service := IngestService{
+	format, err := event.ParseFormat(wireFormat)
+	if err != nil {
+		log.Fatalf("FATAL: KAFKA_WIRE_FORMAT: %v", err)
+	}
+
+	registry, err := event.LoadRegistry(registryPath)
+	if err != nil {
+		log.Fatalf("FATAL: can't load event registry: %v", err)
+	}
+	log.Printf("Loaded event registry with %d event types", len(registry.EventTypes))
+
+	spool, err := OpenSpool(spoolDir)
+	if err != nil {
+		log.Fatalf("FATAL: can't open spool dir %s: %v", spoolDir, err)
+	}
+	spool.RegisterMetrics()
+
+	if bufferSize < maxBatchEvents {
+		log.Fatalf("FATAL: INGEST_BUFFER_SIZE must be at least %d, got %d", maxBatchEvents, bufferSize)
+	}
+
+	eventChannel := make(chan []event.ClickEvent, bufferSize)
+	service := &IngestService{
 		EventChannel: eventChannel,
+		BufferSize:   bufferSize,
+		Spool:        spool,
+		Registry:     registry,
+		Mode:         deliveryMode,
+		Format:       format,
 	}
 
-	http.HandleFunc("/ingest", service.ingestHandler) // here service is one struct copy where event channel has created and know it
-	http.Handle("/metrics", promhttp.Handler())
-	go func() {
-		for event := range eventChannel {
-			data, err := json.Marshal(event)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
-			if err != nil {
-				log.Printf("Error while serializing data: %v", err)
-				continue
-			}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ingest", service.ingestHandler) // here service is one struct copy where event channel has created and know it
+	mux.HandleFunc("/ingest/batch", service.batchHandler)
+	mux.Handle("/metrics", promhttp.Handler())
+	httpServer := &http.Server{Addr: ":8080", Handler: mux}
 
-			ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	drainCtx, stopDrain := context.WithCancel(context.Background())
+	drainDone := make(chan struct{})
+	go func() {
+		spool.Drain(drainCtx, producer, time.Second*5)
+		close(drainDone)
+	}()
 
-			err = producer.WriteMessages(ctx, kafka.Message{
-				Key:   []byte(event.EventId),
-				Value: data,
-			})
+	publishDone := make(chan struct{})
+	go func() {
+		service.publishLoop(eventChannel)
+		close(publishDone)
+	}()
 
-			cancel()
+	lis, err := net.Listen("tcp", grpcAddr)
+	if err != nil {
+		log.Fatalf("FATAL: can't listen on %s: %v", grpcAddr, err)
+	}
 
-			if err != nil {
-				log.Printf("Not able to write message in kafka stream: %v", err)
-				return
-			}
+	grpcServer := grpc.NewServer()
+	pb.RegisterIngestServiceServer(grpcServer, &grpcIngestServer{service: service})
 
+	go func() {
+		log.Printf("gRPC ingestion listening on %s", grpcAddr)
+		if err := grpcServer.Serve(lis); err != nil {
+			log.Printf("ERROR: gRPC server stopped: %v", err)
 		}
 	}()
 
-	fmt.Println("Starting your port at 8080")
+	go func() {
+		fmt.Printf("Starting your port at 8080 (delivery mode: %s, wire format: %s)\n", deliveryMode, format)
+		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Printf("ERROR: Server is not starting : %v \n", err)
+			stop()
+		}
+	}()
 
-	err := http.ListenAndServe(":8080", nil)
+	<-ctx.Done()
+	log.Printf("Shutting down, draining for up to %s", shutdownTimeout)
 
-	if err != nil {
-		log.Printf("ERROR: Server is not starting : %v \n", err)
-		return
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	// 1. stop accepting requests and let in-flight ones finish
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		log.Printf("HTTP shutdown incomplete: %v", err)
+	}
+	stopGRPC(shutdownCtx, grpcServer)
+
+	// 2. publish whatever is still buffered, spooling it if Kafka is down
+	service.closeEvents()
+	select {
+	case <-publishDone:
+	case <-shutdownCtx.Done():
+		log.Printf("ERROR: shutdown deadline hit with %d buffered events unpublished", service.buffered.Load())
+	}
+
+	// 3. stop the periodic replay and give the spool, including what step 2 just spooled, a
+	// last chance to reach Kafka before the deadline; what remains is replayed on restart
+	stopDrain()
+	<-drainDone
+	if err := spool.DrainOnce(shutdownCtx, producer); err != nil {
+		log.Printf("ERROR: final spool drain failed: %v", err)
+	}
+	if depth := spool.Depth(); depth > 0 {
+		log.Printf("%d events remain spooled in %s", depth, spoolDir)
+	}
+
+	// 4. flush and close the Kafka writer
+	if err := producer.Close(); err != nil {
+		log.Printf("Failed to flush kafka writer: %v", err)
 	}
+	spool.Close()
+
+	log.Println("Ingestion stopped")
+}
 
+// stopGRPC drains in-flight RPCs, forcing the server down when ctx expires first.
+func stopGRPC(ctx context.Context, server *grpc.Server) {
+	done := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		log.Printf("gRPC graceful stop timed out, forcing")
+		server.Stop()
+	}
 }
@@ -0,0 +1,352 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	spoolDrainBatch = 100
+	spoolSegmentExt = ".seg"
+)
+
+var (
+	spoolSegmentBytes = 16 << 20 // rotate segments at 16 MiB
+
+	spooledTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "ingestion_spool_appended_total",
+		Help: "Events written to the on-disk spool because Kafka was unavailable.",
+	})
+	replayedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "ingestion_spool_replayed_total",
+		Help: "Spooled events successfully replayed to Kafka.",
+	})
+)
+
+// messageWriter is the part of *kafka.Writer the drainer needs.
+type messageWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+}
+
+// spoolRecord is one line of a segment file.
+type spoolRecord struct {
+	Key       []byte         `json:"key"`
+	Value     []byte         `json:"value"`
+	Headers   []kafka.Header `json:"headers,omitempty"`
+	SpooledAt time.Time      `json:"spooled_at"`
+}
+
+type spoolSegment struct {
+	seq    uint64
+	path   string
+	count  int
+	oldest time.Time
+}
+
+// Spool is a write-ahead log of Kafka messages that could not be delivered.
+// Messages are appended to numbered segment files; the drainer replays sealed
+// segments oldest first and deletes each one once Kafka has accepted all of it.
+// Replay is at-least-once: a segment that fails halfway is retried from the
+// start, and the processor drops the duplicates via ON CONFLICT (event_id).
+type Spool struct {
+	mu         sync.Mutex
+	dir        string
+	segments   []*spoolSegment // oldest first, the last one may be active
+	active     *os.File
+	activeSize int64
+	nextSeq    uint64
+}
+
+func OpenSpool(dir string) (*Spool, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	s := &Spool{dir: dir, nextSeq: 1}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+spoolSegmentExt))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range paths {
+		seq, err := strconv.ParseUint(strings.TrimSuffix(filepath.Base(path), spoolSegmentExt), 10, 64)
+		if err != nil {
+			log.Printf("Ignoring unexpected file in spool dir: %s", path)
+			continue
+		}
+		records, err := readSegment(path)
+		if err != nil {
+			return nil, err
+		}
+		if len(records) == 0 {
+			os.Remove(path)
+			continue
+		}
+		s.segments = append(s.segments, &spoolSegment{
+			seq:    seq,
+			path:   path,
+			count:  len(records),
+			oldest: records[0].SpooledAt,
+		})
+		if seq >= s.nextSeq {
+			s.nextSeq = seq + 1
+		}
+	}
+
+	sort.Slice(s.segments, func(i, j int) bool { return s.segments[i].seq < s.segments[j].seq })
+
+	if depth := s.Depth(); depth > 0 {
+		log.Printf("Recovered %d spooled events from %s", depth, dir)
+	}
+	return s, nil
+}
+
+// readSegment decodes every complete record in a segment. A torn last line from
+// a crash mid-write is skipped.
+func readSegment(path string) ([]spoolRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []spoolRecord
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), spoolSegmentBytes)
+	for scanner.Scan() {
+		var record spoolRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			log.Printf("Skipping corrupt record in %s: %v", path, err)
+			continue
+		}
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
+
+func (s *Spool) RegisterMetrics() {
+	prometheus.MustRegister(
+		spooledTotal,
+		replayedTotal,
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "ingestion_spool_depth_events",
+			Help: "Events currently waiting in the on-disk spool.",
+		}, func() float64 { return float64(s.Depth()) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "ingestion_spool_oldest_age_seconds",
+			Help: "Age of the oldest event in the on-disk spool, 0 when empty.",
+		}, func() float64 { return s.OldestAge().Seconds() }),
+	)
+}
+
+func (s *Spool) Depth() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	depth := 0
+	for _, segment := range s.segments {
+		depth += segment.count
+	}
+	return depth
+}
+
+func (s *Spool) OldestAge() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.segments) == 0 {
+		return 0
+	}
+	return time.Since(s.segments[0].oldest)
+}
+
+// Append durably writes messages to the active segment, rotating it when full.
+func (s *Spool) Append(messages ...kafka.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.active == nil || s.activeSize >= int64(spoolSegmentBytes) {
+		if err := s.rotateLocked(); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	var buf []byte
+	for _, msg := range messages {
+		line, err := json.Marshal(spoolRecord{Key: msg.Key, Value: msg.Value, Headers: msg.Headers, SpooledAt: now})
+		if err != nil {
+			return err
+		}
+		buf = append(buf, line...)
+		buf = append(buf, '\n')
+	}
+
+	if n, err := s.active.Write(buf); err != nil {
+		// cut off the partial write so the next append doesn't continue a torn line; if
+		// that fails, seal the segment and leave the torn line for readSegment to skip
+		if n > 0 {
+			if terr := s.active.Truncate(s.activeSize); terr != nil {
+				log.Printf("Failed to truncate partial spool write: %v", terr)
+				s.sealLocked()
+			}
+		}
+		return err
+	}
+	// the records are in the file now and will be replayed even if the sync fails
+	s.activeSize += int64(len(buf))
+	segment := s.segments[len(s.segments)-1]
+	if segment.count == 0 {
+		segment.oldest = now
+	}
+	segment.count += len(messages)
+
+	if err := s.active.Sync(); err != nil {
+		return err
+	}
+	spooledTotal.Add(float64(len(messages)))
+	return nil
+}
+
+// rotateLocked seals the active segment and opens a new one.
+func (s *Spool) rotateLocked() error {
+	s.sealLocked()
+
+	path := filepath.Join(s.dir, fmt.Sprintf("%020d%s", s.nextSeq, spoolSegmentExt))
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+
+	s.segments = append(s.segments, &spoolSegment{seq: s.nextSeq, path: path})
+	s.nextSeq++
+	s.active = f
+	s.activeSize = 0
+	return nil
+}
+
+func (s *Spool) sealLocked() {
+	if s.active == nil {
+		return
+	}
+	if err := s.active.Close(); err != nil {
+		log.Printf("Failed to close spool segment: %v", err)
+	}
+	s.active = nil
+	// drop an active segment nothing was written to
+	if last := s.segments[len(s.segments)-1]; last.count == 0 {
+		os.Remove(last.path)
+		s.segments = s.segments[:len(s.segments)-1]
+	}
+}
+
+// oldestSealed returns the oldest segment, sealing it first if it is the active one.
+func (s *Spool) oldestSealed() *spoolSegment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.segments) == 0 {
+		return nil
+	}
+	if s.active != nil && len(s.segments) == 1 {
+		s.sealLocked()
+		if len(s.segments) == 0 {
+			return nil
+		}
+	}
+	return s.segments[0]
+}
+
+func (s *Spool) removeSegment(segment *spoolSegment) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(segment.path); err != nil {
+		log.Printf("Failed to remove drained spool segment %s: %v", segment.path, err)
+	}
+	if len(s.segments) > 0 && s.segments[0] == segment {
+		s.segments = s.segments[1:]
+	}
+}
+
+// Drain replays spooled segments to Kafka every interval until ctx is done.
+func (s *Spool) Drain(ctx context.Context, producer messageWriter, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := s.DrainOnce(ctx, producer); err != nil {
+			log.Printf("Kafka still unavailable, %d events remain spooled: %v", s.Depth(), err)
+		}
+	}
+}
+
+// DrainOnce replays every spooled segment to Kafka, oldest first, stopping at the first
+// segment that fails.
+func (s *Spool) DrainOnce(ctx context.Context, producer messageWriter) error {
+	for {
+		segment := s.oldestSealed()
+		if segment == nil {
+			return nil
+		}
+		if err := s.replaySegment(ctx, producer, segment); err != nil {
+			return err
+		}
+		s.removeSegment(segment)
+		log.Printf("Replayed spool segment %s (%d events)", filepath.Base(segment.path), segment.count)
+	}
+}
+
+func (s *Spool) replaySegment(ctx context.Context, producer messageWriter, segment *spoolSegment) error {
+	records, err := readSegment(segment.path)
+	if err != nil {
+		return err
+	}
+
+	for start := 0; start < len(records); start += spoolDrainBatch {
+		end := min(start+spoolDrainBatch, len(records))
+
+		messages := make([]kafka.Message, 0, end-start)
+		for _, record := range records[start:end] {
+			messages = append(messages, kafka.Message{Key: record.Key, Value: record.Value, Headers: record.Headers})
+		}
+
+		writeCtx, cancel := context.WithTimeout(ctx, time.Second*10)
+		err := producer.WriteMessages(writeCtx, messages...)
+		cancel()
+
+		if err != nil {
+			return err
+		}
+		replayedTotal.Add(float64(len(messages)))
+	}
+	return nil
+}
+
+func (s *Spool) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sealLocked()
+	return nil
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// fakeWriter records the messages written to it, or fails every write with err.
+type fakeWriter struct {
+	err      error
+	messages []kafka.Message
+}
+
+func (w *fakeWriter) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	if w.err != nil {
+		return w.err
+	}
+	w.messages = append(w.messages, msgs...)
+	return nil
+}
+
+func (w *fakeWriter) keys() []string {
+	var keys []string
+	for _, msg := range w.messages {
+		keys = append(keys, string(msg.Key))
+	}
+	return keys
+}
+
+func message(key string) kafka.Message {
+	return kafka.Message{Key: []byte(key), Value: []byte(`{"user_id":"` + key + `"}`)}
+}
+
+func segmentFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+spoolSegmentExt))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, path := range paths {
+		names = append(names, filepath.Base(path))
+	}
+	return names
+}
+
+func TestSpoolRotation(t *testing.T) {
+	defer func(size int) { spoolSegmentBytes = size }(spoolSegmentBytes)
+	spoolSegmentBytes = 1 // every append after the first starts a new segment
+
+	dir := t.TempDir()
+	spool, err := OpenSpool(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, batch := range [][]kafka.Message{
+		{message("a"), message("b")},
+		{message("c")},
+		{message("d"), message("e"), message("f")},
+	} {
+		if err := spool.Append(batch...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	spool.Close()
+
+	want := []string{"00000000000000000001.seg", "00000000000000000002.seg", "00000000000000000003.seg"}
+	if got := segmentFiles(t, dir); !slices.Equal(got, want) {
+		t.Fatalf("segments = %q, want %q", got, want)
+	}
+	if depth := spool.Depth(); depth != 6 {
+		t.Errorf("Depth = %d, want 6", depth)
+	}
+
+	reopened, err := OpenSpool(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if depth := reopened.Depth(); depth != 6 {
+		t.Errorf("Depth after reopening = %d, want 6", depth)
+	}
+	if err := reopened.Append(message("g")); err != nil {
+		t.Fatal(err)
+	}
+	reopened.Close()
+	if got := segmentFiles(t, dir); len(got) != 4 || got[3] != "00000000000000000004.seg" {
+		t.Errorf("segments = %q, want a new segment after the recovered ones", got)
+	}
+}
+
+func TestOpenSpoolRecovery(t *testing.T) {
+	dir := t.TempDir()
+	oldest := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	line := func(key string, spooledAt time.Time) string {
+		b, err := json.Marshal(spoolRecord{Key: []byte(key), Value: []byte(`{}`), SpooledAt: spooledAt})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b) + "\n"
+	}
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the crash tore the last line of the newest segment
+	write("00000000000000000007.seg", line("c", oldest.Add(time.Minute))+line("d", oldest.Add(time.Minute))+`{"key":"ZQ==","val`)
+	write("00000000000000000005.seg", line("a", oldest)+line("b", oldest))
+	write("00000000000000000006.seg", "")
+	write("notes.seg", line("x", oldest))
+
+	spool, err := OpenSpool(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer spool.Close()
+
+	if depth := spool.Depth(); depth != 4 {
+		t.Errorf("Depth = %d, want 4 complete records", depth)
+	}
+	before := time.Since(oldest)
+	if age := spool.OldestAge(); age < before || age > before+time.Minute {
+		t.Errorf("OldestAge = %s, want about %s", age, before)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "00000000000000000006.seg")); !os.IsNotExist(err) {
+		t.Errorf("empty segment not removed: %v", err)
+	}
+
+	if err := spool.Append(message("e")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "00000000000000000008.seg")); err != nil {
+		t.Errorf("append did not continue after the highest recovered segment: %v", err)
+	}
+
+	writer := &fakeWriter{}
+	if err := spool.DrainOnce(context.Background(), writer); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a", "b", "c", "d", "e"}; !slices.Equal(writer.keys(), want) {
+		t.Errorf("replayed %q, want %q oldest segment first", writer.keys(), want)
+	}
+	if got := segmentFiles(t, dir); !slices.Equal(got, []string{"notes.seg"}) {
+		t.Errorf("segments left after draining = %q, want only the unexpected file", got)
+	}
+}
+
+func TestSpoolDrainOnce(t *testing.T) {
+	dir := t.TempDir()
+	spool, err := OpenSpool(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer spool.Close()
+
+	if depth, age := spool.Depth(), spool.OldestAge(); depth != 0 || age != 0 {
+		t.Errorf("empty spool: Depth = %d, OldestAge = %s, want 0, 0", depth, age)
+	}
+	if err := spool.DrainOnce(context.Background(), &fakeWriter{}); err != nil {
+		t.Errorf("DrainOnce on an empty spool: %v", err)
+	}
+
+	if err := spool.Append(message("a"), message("b")); err != nil {
+		t.Fatal(err)
+	}
+	if depth, age := spool.Depth(), spool.OldestAge(); depth != 2 || age <= 0 || age > time.Minute {
+		t.Errorf("Depth = %d, OldestAge = %s, want 2 and just spooled", depth, age)
+	}
+
+	// a failed replay seals the active segment but keeps it whole
+	broken := errors.New("kafka unavailable")
+	if err := spool.DrainOnce(context.Background(), &fakeWriter{err: broken}); !errors.Is(err, broken) {
+		t.Fatalf("DrainOnce error = %v, want %v", err, broken)
+	}
+	if depth := spool.Depth(); depth != 2 {
+		t.Errorf("Depth after a failed replay = %d, want 2", depth)
+	}
+
+	// appends after sealing go to a new segment, replayed after the sealed one
+	if err := spool.Append(message("c")); err != nil {
+		t.Fatal(err)
+	}
+	if got := segmentFiles(t, dir); len(got) != 2 {
+		t.Errorf("segments = %q, want the sealed one and a new active one", got)
+	}
+
+	writer := &fakeWriter{}
+	if err := spool.DrainOnce(context.Background(), writer); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a", "b", "c"}; !slices.Equal(writer.keys(), want) {
+		t.Errorf("replayed %q, want %q", writer.keys(), want)
+	}
+	if string(writer.messages[0].Value) != `{"user_id":"a"}` {
+		t.Errorf("replayed value %s, want the spooled one", writer.messages[0].Value)
+	}
+	if depth, age := spool.Depth(), spool.OldestAge(); depth != 0 || age != 0 {
+		t.Errorf("drained spool: Depth = %d, OldestAge = %s, want 0, 0", depth, age)
+	}
+	if got := segmentFiles(t, dir); len(got) != 0 {
+		t.Errorf("segments left after draining = %q", got)
+	}
+
+	if err := spool.Append(message("d")); err != nil {
+		t.Fatal(err)
+	}
+	if depth := spool.Depth(); depth != 1 {
+		t.Errorf("Depth after appending to a drained spool = %d, want 1", depth)
+	}
+}
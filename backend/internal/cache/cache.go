@@ -0,0 +1,14 @@
+// Package cache names the Redis keys of the counts GetEventCount caches and the processor
+// invalidates.
+package cache
+
+// CountKey is the cached count of a user on a page. An empty userID or pageURL stands for
+// every user or every page, an empty eventType for every event type, so each combination
+// gets its own key. Counts across all event types keep the original two-part key.
+func CountKey(userID, pageURL, eventType string) string {
+	key := userID + "__+__" + pageURL
+	if eventType != "" {
+		key += "__+__" + eventType
+	}
+	return key
+}
@@ -0,0 +1,58 @@
+// Package config holds the environment helpers shared by all services.
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// GetEnv returns the value of key, or fallback when it is unset or empty.
+func GetEnv(key, fallback string) string {
+	value := os.Getenv(key)
+	if value != "" {
+		return value
+	}
+	return fallback
+}
+
+// RedisAddr returns REDIS_ADDR, falling back to REDIS_HOST:REDIS_PORT.
+func RedisAddr() string {
+	addr := os.Getenv("REDIS_ADDR")
+	if addr == "" {
+		host := GetEnv("REDIS_HOST", "localhost")
+		port := GetEnv("REDIS_PORT", "6379")
+		addr = host + ":" + port
+	}
+	return addr
+}
+
+// GetDuration parses key as a time.Duration such as "30s", returning fallback when it is
+// unset or malformed.
+func GetDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("Invalid %s=%q, using %s: %v", key, value, fallback, err)
+		return fallback
+	}
+	return d
+}
+
+// GetInt parses key as an integer, returning fallback when it is unset or malformed.
+func GetInt(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Invalid %s=%q, using %d: %v", key, value, fallback, err)
+		return fallback
+	}
+	return n
+}
@@ -0,0 +1,154 @@
+// Package event is the canonical click event model shared by every service: the
+// struct itself, its registry validation and its Kafka encoding.
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	pb "event-analytics/proto/event-analytics/proto"
+
+	"github.com/segmentio/kafka-go"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type ClickEvent struct {
+	EventId    string            `json:"event_id"`
+	UserId     string            `json:"user_id"`
+	EventType  string            `json:"event_type"`
+	PageUrl    string            `json:"page_url"`
+	TimeStamp  time.Time         `json:"time_stamp"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+// PropertiesJSON returns the properties as a JSON object for the click_events.properties column.
+func (e ClickEvent) PropertiesJSON() string {
+	if len(e.Properties) == 0 {
+		return "{}"
+	}
+	data, err := json.Marshal(e.Properties)
+	if err != nil {
+		return "{}"
+	}
+	return string(data)
+}
+
+// Kafka message headers describing the payload. Messages without a content-type
+// header predate them and are JSON.
+const (
+	HeaderContentType   = "content-type"
+	HeaderSchemaVersion = "schema-version"
+
+	ContentTypeJSON     = "application/json"
+	ContentTypeProtobuf = "application/x-protobuf"
+
+	SchemaVersion = "1"
+)
+
+// Format selects the payload encoding producers write.
+type Format string
+
+const (
+	FormatJSON     Format = "json"
+	FormatProtobuf Format = "protobuf"
+)
+
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case FormatJSON, FormatProtobuf:
+		return Format(s), nil
+	}
+	return "", fmt.Errorf("unknown wire format %q, want %q or %q", s, FormatJSON, FormatProtobuf)
+}
+
+// Encode builds the Kafka message for an event, keyed by user_id so that a user's events share
+// a partition and are consumed in order.
+func Encode(e ClickEvent, format Format) (kafka.Message, error) {
+	var (
+		data        []byte
+		contentType string
+		err         error
+	)
+
+	switch format {
+	case FormatProtobuf:
+		contentType = ContentTypeProtobuf
+		data, err = proto.Marshal(e.ToProto())
+	case FormatJSON:
+		contentType = ContentTypeJSON
+		data, err = json.Marshal(e)
+	default:
+		err = fmt.Errorf("unknown wire format %q", format)
+	}
+	if err != nil {
+		return kafka.Message{}, err
+	}
+
+	return kafka.Message{
+		Key:   []byte(e.UserId),
+		Value: data,
+		Headers: []kafka.Header{
+			{Key: HeaderContentType, Value: []byte(contentType)},
+			{Key: HeaderSchemaVersion, Value: []byte(SchemaVersion)},
+		},
+	}, nil
+}
+
+// Decode parses a message in any format Encode has produced, including legacy JSON
+// messages that carry no headers.
+func Decode(msg kafka.Message) (ClickEvent, error) {
+	contentType := ContentTypeJSON
+	for _, h := range msg.Headers {
+		switch h.Key {
+		case HeaderContentType:
+			contentType = string(h.Value)
+		case HeaderSchemaVersion:
+			if string(h.Value) != SchemaVersion {
+				return ClickEvent{}, fmt.Errorf("unsupported schema version %q", h.Value)
+			}
+		}
+	}
+
+	var e ClickEvent
+	switch contentType {
+	case ContentTypeJSON:
+		err := json.Unmarshal(msg.Value, &e)
+		return e, err
+	case ContentTypeProtobuf:
+		var p pb.ClickEvent
+		if err := proto.Unmarshal(msg.Value, &p); err != nil {
+			return e, err
+		}
+		return FromProto(&p), nil
+	}
+	return e, fmt.Errorf("unsupported content type %q", contentType)
+}
+
+// ToProto converts the event to its wire message.
+func (e ClickEvent) ToProto() *pb.ClickEvent {
+	return &pb.ClickEvent{
+		EventId:    e.EventId,
+		UserId:     e.UserId,
+		EventType:  e.EventType,
+		PageUrl:    e.PageUrl,
+		TimeStamp:  timestamppb.New(e.TimeStamp),
+		Properties: e.Properties,
+	}
+}
+
+// FromProto converts a wire message back to an event.
+func FromProto(p *pb.ClickEvent) ClickEvent {
+	e := ClickEvent{
+		EventId:    p.EventId,
+		UserId:     p.UserId,
+		EventType:  p.EventType,
+		PageUrl:    p.PageUrl,
+		Properties: p.Properties,
+	}
+	if p.TimeStamp != nil {
+		e.TimeStamp = p.TimeStamp.AsTime()
+	}
+	return e
+}
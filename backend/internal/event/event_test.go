@@ -0,0 +1,109 @@
+package event
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	e := ClickEvent{
+		EventId:    "0192f8a4-6b1e-7c3d-8e2f-1a2b3c4d5e6f",
+		UserId:     "user_1",
+		EventType:  "click",
+		PageUrl:    "https://example.com/pricing",
+		TimeStamp:  time.Date(2025, 11, 10, 12, 30, 15, 123456789, time.UTC),
+		Properties: map[string]string{"plan": "pro", "button_id": "cta"},
+	}
+
+	for _, format := range []Format{FormatJSON, FormatProtobuf} {
+		t.Run(string(format), func(t *testing.T) {
+			msg, err := Encode(e, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(msg.Key) != e.UserId {
+				t.Errorf("key = %q, want the user_id %q", msg.Key, e.UserId)
+			}
+
+			got, err := Decode(msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.TimeStamp.Equal(e.TimeStamp) {
+				t.Errorf("time_stamp = %v, want %v", got.TimeStamp, e.TimeStamp)
+			}
+			got.TimeStamp = e.TimeStamp
+			if !reflect.DeepEqual(got, e) {
+				t.Errorf("decoded %+v, want %+v", got, e)
+			}
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	legacy := []byte(`{"event_id":"evt_1","user_id":"user_1","event_type":"click","page_url":"/home","time_stamp":"2025-11-10T12:00:00Z"}`)
+	header := func(key, value string) kafka.Header { return kafka.Header{Key: key, Value: []byte(value)} }
+
+	tests := []struct {
+		name    string
+		msg     kafka.Message
+		wantErr string
+	}{
+		{
+			name: "legacy json without headers",
+			msg:  kafka.Message{Value: legacy},
+		},
+		{
+			name: "json with headers",
+			msg:  kafka.Message{Value: legacy, Headers: []kafka.Header{header(HeaderContentType, ContentTypeJSON), header(HeaderSchemaVersion, SchemaVersion)}},
+		},
+		{
+			name: "json without a schema version",
+			msg:  kafka.Message{Value: legacy, Headers: []kafka.Header{header(HeaderContentType, ContentTypeJSON)}},
+		},
+		{
+			name:    "unknown schema version",
+			msg:     kafka.Message{Value: legacy, Headers: []kafka.Header{header(HeaderContentType, ContentTypeJSON), header(HeaderSchemaVersion, "2")}},
+			wantErr: `unsupported schema version "2"`,
+		},
+		{
+			name:    "unknown content type",
+			msg:     kafka.Message{Value: legacy, Headers: []kafka.Header{header(HeaderContentType, "application/avro")}},
+			wantErr: `unsupported content type "application/avro"`,
+		},
+		{
+			name:    "json declared as protobuf",
+			msg:     kafka.Message{Value: legacy, Headers: []kafka.Header{header(HeaderContentType, ContentTypeProtobuf)}},
+			wantErr: "proto",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Decode(tt.msg)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Decode error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := ClickEvent{
+				EventId:   "evt_1",
+				UserId:    "user_1",
+				EventType: "click",
+				PageUrl:   "/home",
+				TimeStamp: time.Date(2025, 11, 10, 12, 0, 0, 0, time.UTC),
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("decoded %+v, want %+v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,266 @@
+package event
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// defaultRegistry is used when EVENT_REGISTRY_PATH is not set.
+//
+//go:embed event_types.json
+var defaultRegistry []byte
+
+// Duration is a time.Duration written as a string like "5m" in the registry file.
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+type EventTypeRule struct {
+	Required     []string       `json:"required"`
+	MaxLengths   map[string]int `json:"max_lengths"`
+	NormalizeURL bool           `json:"normalize_url"`
+	MaxAge       Duration       `json:"max_age"`    // how far in the past time_stamp may be, 0 means unbounded
+	MaxFuture    Duration       `json:"max_future"` // how far ahead of server time time_stamp may be
+
+	MaxProperties          int `json:"max_properties"` // 0 means properties are not allowed
+	MaxPropertyKeyLength   int `json:"max_property_key_length"`
+	MaxPropertyValueLength int `json:"max_property_value_length"`
+}
+
+type EventRegistry struct {
+	EventTypes map[string]EventTypeRule `json:"event_types"`
+
+	// common holds the checks every event type makes, applied when event_type is missing
+	// or unknown so the other failing fields are still reported
+	common EventTypeRule
+}
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+type ValidationError struct {
+	Fields []FieldError `json:"fields"`
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		parts = append(parts, f.Field+" "+f.Message)
+	}
+	return "validation failed: " + strings.Join(parts, ", ")
+}
+
+// stringFields are the ClickEvent fields a rule can reference by their JSON name.
+var stringFields = map[string]func(*ClickEvent) *string{
+	"event_id":   func(e *ClickEvent) *string { return &e.EventId },
+	"user_id":    func(e *ClickEvent) *string { return &e.UserId },
+	"event_type": func(e *ClickEvent) *string { return &e.EventType },
+	"page_url":   func(e *ClickEvent) *string { return &e.PageUrl },
+}
+
+// LoadRegistry reads the registry from path, or the built-in one when path is empty.
+func LoadRegistry(path string) (*EventRegistry, error) {
+	data := defaultRegistry
+	if path != "" {
+		var err error
+		data, err = os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var registry EventRegistry
+	if err := json.Unmarshal(data, &registry); err != nil {
+		return nil, fmt.Errorf("parse event registry: %w", err)
+	}
+
+	if len(registry.EventTypes) == 0 {
+		return nil, fmt.Errorf("event registry defines no event types")
+	}
+	for name, rule := range registry.EventTypes {
+		for _, field := range rule.Required {
+			if _, ok := stringFields[field]; !ok {
+				return nil, fmt.Errorf("event type %q requires unknown field %q", name, field)
+			}
+		}
+		for field := range rule.MaxLengths {
+			if _, ok := stringFields[field]; !ok {
+				return nil, fmt.Errorf("event type %q limits unknown field %q", name, field)
+			}
+		}
+	}
+	registry.common = commonRule(registry.EventTypes)
+	return &registry, nil
+}
+
+// commonRule keeps the loosest form of each check that every rule makes: the fields all of
+// them require, the largest length limits and time bounds. An event failing it would fail
+// whatever its event_type.
+func commonRule(rules map[string]EventTypeRule) EventTypeRule {
+	var common EventTypeRule
+	first := true
+	for _, name := range slices.Sorted(maps.Keys(rules)) {
+		rule := rules[name]
+		if first {
+			common = rule
+			common.Required = slices.Clone(rule.Required)
+			common.MaxLengths = maps.Clone(rule.MaxLengths)
+			first = false
+			continue
+		}
+
+		common.Required = slices.DeleteFunc(common.Required, func(field string) bool {
+			return !slices.Contains(rule.Required, field)
+		})
+		for field, current := range common.MaxLengths {
+			if limit, ok := rule.MaxLengths[field]; !ok {
+				delete(common.MaxLengths, field)
+			} else if limit > current {
+				common.MaxLengths[field] = limit
+			}
+		}
+		common.NormalizeURL = common.NormalizeURL && rule.NormalizeURL
+		common.MaxAge = loosest(common.MaxAge, rule.MaxAge)
+		common.MaxFuture = loosest(common.MaxFuture, rule.MaxFuture)
+		common.MaxProperties = max(common.MaxProperties, rule.MaxProperties)
+		common.MaxPropertyKeyLength = loosest(common.MaxPropertyKeyLength, rule.MaxPropertyKeyLength)
+		common.MaxPropertyValueLength = loosest(common.MaxPropertyValueLength, rule.MaxPropertyValueLength)
+	}
+	return common
+}
+
+// loosest returns the larger of two limits where 0 means unbounded.
+func loosest[T ~int | ~int64](a, b T) T {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return max(a, b)
+}
+
+// Validate checks the event against the rule for its event_type and normalizes page_url
+// in place. It returns nil when the event is valid. A missing or unknown event_type is
+// reported along with the fields failing the checks every event type makes.
+func (r *EventRegistry) Validate(event *ClickEvent, now time.Time) *ValidationError {
+	var fields []FieldError
+
+	rule, ok := r.EventTypes[event.EventType]
+	switch {
+	case event.EventType == "":
+		fields = append(fields, FieldError{Field: "event_type", Message: "is required"})
+		rule = r.common
+	case !ok:
+		fields = append(fields, FieldError{Field: "event_type", Message: fmt.Sprintf("unknown event type %q", event.EventType)})
+		rule = r.common
+	}
+
+	for _, field := range rule.Required {
+		if strings.TrimSpace(*stringFields[field](event)) == "" {
+			fields = append(fields, FieldError{Field: field, Message: "is required"})
+		}
+	}
+
+	if rule.NormalizeURL && event.PageUrl != "" {
+		normalized, err := NormalizeURL(event.PageUrl)
+		if err != nil {
+			fields = append(fields, FieldError{Field: "page_url", Message: "is not a valid URL"})
+		} else {
+			event.PageUrl = normalized
+		}
+	}
+
+	for _, field := range slices.Sorted(maps.Keys(rule.MaxLengths)) {
+		if n, max := len(*stringFields[field](event)), rule.MaxLengths[field]; n > max {
+			fields = append(fields, FieldError{Field: field, Message: fmt.Sprintf("is %d bytes, max is %d", n, max)})
+		}
+	}
+
+	if rule.MaxAge > 0 && event.TimeStamp.Before(now.Add(-time.Duration(rule.MaxAge))) {
+		fields = append(fields, FieldError{Field: "time_stamp", Message: fmt.Sprintf("is older than %s", time.Duration(rule.MaxAge))})
+	}
+	if rule.MaxFuture > 0 && event.TimeStamp.After(now.Add(time.Duration(rule.MaxFuture))) {
+		fields = append(fields, FieldError{Field: "time_stamp", Message: fmt.Sprintf("is more than %s in the future", time.Duration(rule.MaxFuture))})
+	}
+
+	fields = append(fields, validateProperties(rule, event.Properties)...)
+
+	if len(fields) == 0 {
+		return nil
+	}
+	return &ValidationError{Fields: fields}
+}
+
+func validateProperties(rule EventTypeRule, properties map[string]string) []FieldError {
+	if len(properties) == 0 {
+		return nil
+	}
+	if len(properties) > rule.MaxProperties {
+		return []FieldError{{Field: "properties", Message: fmt.Sprintf("has %d entries, max is %d", len(properties), rule.MaxProperties)}}
+	}
+
+	var fields []FieldError
+	for _, key := range slices.Sorted(maps.Keys(properties)) {
+		field := "properties." + key
+		switch {
+		case strings.TrimSpace(key) == "":
+			fields = append(fields, FieldError{Field: "properties", Message: "has an empty key"})
+		case rule.MaxPropertyKeyLength > 0 && len(key) > rule.MaxPropertyKeyLength:
+			fields = append(fields, FieldError{Field: field, Message: fmt.Sprintf("key is %d bytes, max is %d", len(key), rule.MaxPropertyKeyLength)})
+		case rule.MaxPropertyValueLength > 0 && len(properties[key]) > rule.MaxPropertyValueLength:
+			fields = append(fields, FieldError{Field: field, Message: fmt.Sprintf("value is %d bytes, max is %d", len(properties[key]), rule.MaxPropertyValueLength)})
+		}
+	}
+	return fields
+}
+
+// NormalizeURL gives equivalent URLs one spelling so they aggregate into the same
+// page_clicks row, and so queries can filter on that spelling: lower-case scheme and host,
+// no default port, no fragment, sorted query parameters and no trailing slash. Relative
+// paths like "/pricing" are allowed.
+func NormalizeURL(raw string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme == "" && u.Host == "" && !strings.HasPrefix(u.Path, "/") {
+		return "", fmt.Errorf("url %q is neither absolute nor a path", raw)
+	}
+	if u.Scheme != "" && ((u.Scheme != "http" && u.Scheme != "https") || u.Host == "") {
+		return "", fmt.Errorf("url %q is not an http(s) URL", raw)
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	if (u.Scheme == "http" && u.Port() == "80") || (u.Scheme == "https" && u.Port() == "443") {
+		u.Host = u.Hostname()
+	}
+	u.Fragment = ""
+	u.RawFragment = ""
+	if u.RawQuery != "" {
+		u.RawQuery = u.Query().Encode()
+	}
+	if len(u.Path) > 1 || u.Host != "" {
+		u.Path = strings.TrimRight(u.Path, "/")
+		u.RawPath = ""
+	}
+	return u.String(), nil
+}
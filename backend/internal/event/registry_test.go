@@ -0,0 +1,181 @@
+package event
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNormalizeURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		{name: "path stays as is", raw: "/pricing", want: "/pricing"},
+		{name: "root path keeps its slash", raw: "/", want: "/"},
+		{name: "trailing slash on a path", raw: "/pricing/", want: "/pricing"},
+		{name: "surrounding spaces", raw: "  /pricing  ", want: "/pricing"},
+		{name: "scheme and host lower-cased", raw: "HTTPS://Example.COM/Pricing", want: "https://example.com/Pricing"},
+		{name: "trailing slash on a host", raw: "https://example.com/", want: "https://example.com"},
+		{name: "default http port", raw: "http://example.com:80/docs", want: "http://example.com/docs"},
+		{name: "default https port", raw: "https://example.com:443/docs", want: "https://example.com/docs"},
+		{name: "other port kept", raw: "https://example.com:8443/docs", want: "https://example.com:8443/docs"},
+		{name: "http port on https kept", raw: "https://example.com:80/docs", want: "https://example.com:80/docs"},
+		{name: "fragment dropped", raw: "https://example.com/docs#install", want: "https://example.com/docs"},
+		{name: "query sorted", raw: "/search?q=kafka&page=2&lang=en", want: "/search?lang=en&page=2&q=kafka"},
+		{name: "query sorted after the trailing slash", raw: "https://example.com/search/?b=2&a=1#top", want: "https://example.com/search?a=1&b=2"},
+		{name: "relative path", raw: "pricing", wantErr: true},
+		{name: "other scheme", raw: "ftp://example.com/file", wantErr: true},
+		{name: "scheme without host", raw: "https:/pricing", wantErr: true},
+		{name: "unparseable", raw: "http://[::1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeURL(tt.raw)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("NormalizeURL(%q) = %q, want an error", tt.raw, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NormalizeURL(%q): %v", tt.raw, err)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeURL(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	registry, err := LoadRegistry("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2025, 11, 10, 12, 0, 0, 0, time.UTC)
+	valid := func() ClickEvent {
+		return ClickEvent{
+			UserId:    "user_1",
+			EventType: "click",
+			PageUrl:   "/pricing",
+			TimeStamp: now.Add(-time.Minute),
+		}
+	}
+	properties := func(n int) map[string]string {
+		p := make(map[string]string, n)
+		for i := range n {
+			p[string(rune('a'+i))] = "x"
+		}
+		return p
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(e *ClickEvent)
+		want    []string // "field: message" of each violation, in order
+		wantURL string
+	}{
+		{name: "valid", modify: func(e *ClickEvent) {}, wantURL: "/pricing"},
+		{
+			name:    "page_url normalized",
+			modify:  func(e *ClickEvent) { e.PageUrl = "https://Example.com:443/pricing/?b=2&a=1" },
+			wantURL: "https://example.com/pricing?a=1&b=2",
+		},
+		{
+			name:   "required fields",
+			modify: func(e *ClickEvent) { e.UserId = " "; e.PageUrl = "" },
+			want:   []string{"user_id: is required", "page_url: is required"},
+		},
+		{
+			name:   "invalid page_url",
+			modify: func(e *ClickEvent) { e.PageUrl = "pricing" },
+			want:   []string{"page_url: is not a valid URL"},
+		},
+		{
+			name: "max lengths",
+			modify: func(e *ClickEvent) {
+				e.EventId = strings.Repeat("e", 129)
+				e.UserId = strings.Repeat("u", 129)
+			},
+			want: []string{"event_id: is 129 bytes, max is 128", "user_id: is 129 bytes, max is 128"},
+		},
+		{
+			name:   "max length at the limit",
+			modify: func(e *ClickEvent) { e.UserId = strings.Repeat("u", 128) },
+		},
+		{
+			name:   "older than max_age",
+			modify: func(e *ClickEvent) { e.TimeStamp = now.Add(-169 * time.Hour) },
+			want:   []string{"time_stamp: is older than 168h0m0s"},
+		},
+		{
+			name:   "max_future",
+			modify: func(e *ClickEvent) { e.TimeStamp = now.Add(6 * time.Minute) },
+			want:   []string{"time_stamp: is more than 5m0s in the future"},
+		},
+		{
+			name:   "within max_future",
+			modify: func(e *ClickEvent) { e.TimeStamp = now.Add(5 * time.Minute) },
+		},
+		{
+			name:   "too many properties",
+			modify: func(e *ClickEvent) { e.Properties = properties(21) },
+			want:   []string{"properties: has 21 entries, max is 20"},
+		},
+		{
+			name: "property key and value lengths",
+			modify: func(e *ClickEvent) {
+				e.Properties = map[string]string{
+					strings.Repeat("k", 65): "x",
+					"plan":                  strings.Repeat("v", 257),
+					" ":                     "x",
+				}
+			},
+			want: []string{
+				"properties: has an empty key",
+				"properties." + strings.Repeat("k", 65) + ": key is 65 bytes, max is 64",
+				"properties.plan: value is 257 bytes, max is 256",
+			},
+		},
+		{
+			name:   "missing event_type still lists the other fields",
+			modify: func(e *ClickEvent) { e.EventType = ""; e.UserId = "" },
+			want:   []string{"event_type: is required", "user_id: is required"},
+		},
+		{
+			name: "unknown event_type still lists the other fields",
+			modify: func(e *ClickEvent) {
+				e.EventType = "purchase"
+				e.PageUrl = ""
+				e.TimeStamp = now.Add(-200 * time.Hour)
+			},
+			want: []string{`event_type: unknown event type "purchase"`, "page_url: is required", "time_stamp: is older than 168h0m0s"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := valid()
+			tt.modify(&e)
+
+			var got []string
+			if verr := registry.Validate(&e, now); verr != nil {
+				for _, f := range verr.Fields {
+					got = append(got, f.Field+": "+f.Message)
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("violations = %q, want %q", got, tt.want)
+			}
+			if tt.wantURL != "" && e.PageUrl != tt.wantURL {
+				t.Errorf("page_url = %q, want %q", e.PageUrl, tt.wantURL)
+			}
+		})
+	}
+}
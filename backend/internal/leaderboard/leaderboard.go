@@ -0,0 +1,52 @@
+// Package leaderboard names the Redis sorted sets that count clicks per page and per user
+// for every minute of the real-time horizon. The processor increments them, the analytics
+// service unions a window of them for TopPages and TopUsers.
+package leaderboard
+
+import (
+	"fmt"
+	"time"
+
+	"event-analytics/internal/config"
+)
+
+const (
+	Pages = "pages"
+	Users = "users"
+
+	BucketWidth = time.Minute
+
+	// stands for every event type in a key
+	anyType = "*"
+)
+
+// Horizon is how far back the sorted sets go. Older windows are answered from Postgres.
+var Horizon = config.GetDuration("TOP_N_HORIZON", time.Hour)
+
+// TTL keeps a bucket around until it has left the horizon.
+func TTL() time.Duration {
+	return Horizon + 2*BucketWidth
+}
+
+// Key is the sorted set of a dimension, event type and bucket. An empty eventType means
+// every event type.
+func Key(dimension, eventType string, ts time.Time) string {
+	if eventType == "" {
+		eventType = anyType
+	}
+	return fmt.Sprintf("top:%s:%s:%d", dimension, eventType, ts.UTC().Truncate(BucketWidth).Unix())
+}
+
+// Keys returns the key of every bucket from since up to and including now.
+func Keys(dimension, eventType string, since, now time.Time) []string {
+	var keys []string
+	for bucket := since.UTC().Truncate(BucketWidth); !bucket.After(now); bucket = bucket.Add(BucketWidth) {
+		keys = append(keys, Key(dimension, eventType, bucket))
+	}
+	return keys
+}
+
+// InHorizon reports whether a window starting at since can be answered from Redis.
+func InHorizon(since, now time.Time) bool {
+	return !since.Before(now.Add(-Horizon))
+}
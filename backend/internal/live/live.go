@@ -0,0 +1,33 @@
+// Package live describes the count changes the processor publishes on Redis pub/sub after
+// every batch, and the analytics service streams to SubscribeCounts clients.
+package live
+
+import (
+	"time"
+
+	"event-analytics/internal/config"
+)
+
+// Channel is the pub/sub channel the changes are published on.
+var Channel = config.GetEnv("LIVE_COUNTS_CHANNEL", "counts:live")
+
+// Change is how many clicks a batch added for a user, page and event type.
+type Change struct {
+	UserId    string `json:"user_id"`
+	PageUrl   string `json:"page_url"`
+	EventType string `json:"event_type"`
+	Delta     int64  `json:"delta"`
+}
+
+// Batch is one published message, the changes of one processed batch.
+type Batch struct {
+	Changes     []Change  `json:"changes"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// Matches reports whether a change passes a subscriber's filters, empty filters match anything.
+func (c Change) Matches(userID, pageURL, eventType string) bool {
+	return (userID == "" || c.UserId == userID) &&
+		(pageURL == "" || c.PageUrl == pageURL) &&
+		(eventType == "" || c.EventType == eventType)
+}
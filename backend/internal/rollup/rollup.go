@@ -0,0 +1,52 @@
+// Package rollup describes the time-bucketed click count tables the processor maintains
+// and the analytics service reads.
+package rollup
+
+import (
+	"time"
+
+	"event-analytics/internal/config"
+)
+
+// Table holds click counts per (bucket, page_url, user_id, event_type), where bucket is the
+// event time_stamp truncated to Width in UTC.
+type Table struct {
+	Name  string
+	Width time.Duration
+	// buckets older than this are deleted, 0 keeps them forever
+	Retention time.Duration
+}
+
+var (
+	Minute = Table{Name: "clicks_per_minute", Width: time.Minute, Retention: config.GetDuration("ROLLUP_RETENTION_MINUTE", 48*time.Hour)}
+	Hour   = Table{Name: "clicks_per_hour", Width: time.Hour, Retention: config.GetDuration("ROLLUP_RETENTION_HOUR", 90*24*time.Hour)}
+	Day    = Table{Name: "clicks_per_day", Width: 24 * time.Hour, Retention: config.GetDuration("ROLLUP_RETENTION_DAY", 0)}
+
+	// Tables lists every rollup, finest first.
+	Tables = []Table{Minute, Hour, Day}
+)
+
+// Bucket truncates t to the table's bucket.
+func (t Table) Bucket(ts time.Time) time.Time {
+	return ts.UTC().Truncate(t.Width)
+}
+
+// Cutoff is the oldest bucket kept at now, older ones are past retention.
+func (t Table) Cutoff(now time.Time) time.Time {
+	return t.Bucket(now.Add(-t.Retention))
+}
+
+// Expired reports whether a bucket is past retention.
+func (t Table) Expired(bucket, now time.Time) bool {
+	return t.Retention > 0 && bucket.Before(t.Cutoff(now))
+}
+
+// Covering returns the finest table that still holds every bucket since start.
+func Covering(start, now time.Time) Table {
+	for _, t := range Tables {
+		if !t.Expired(t.Bucket(start), now) {
+			return t
+		}
+	}
+	return Tables[len(Tables)-1]
+}
@@ -0,0 +1,125 @@
+// Package uniques names the Redis HyperLogLogs of distinct user_ids per page and per site.
+// The processor PFADDs every event into an hourly and a daily key; the analytics service
+// PFCOUNTs the keys covering a range, which merges them into one distinct count.
+package uniques
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"event-analytics/internal/config"
+)
+
+const (
+	Hour = "hour"
+	Day  = "day"
+
+	// Redis HyperLogLogs have a standard error of 0.81%
+	StandardError = 0.0081
+
+	// stands for every page in a key
+	anyPage = "*"
+
+	// keys one PFCOUNT may merge, about 200 days of daily keys
+	MaxKeys = 200
+)
+
+var (
+	ErrExpired     = errors.New("range is older than the unique users retention")
+	ErrTooManyKeys = fmt.Errorf("range needs more than %d keys, narrow it", MaxKeys)
+)
+
+var (
+	// hourly keys give ranges hour precision, older hours fall back to their whole day
+	HourRetention = config.GetDuration("UNIQUES_HOUR_RETENTION", 7*24*time.Hour)
+	DayRetention  = config.GetDuration("UNIQUES_DAY_RETENTION", 400*24*time.Hour)
+)
+
+// Width is the length of a bucket of the given granularity.
+func Width(granularity string) time.Duration {
+	if granularity == Day {
+		return 24 * time.Hour
+	}
+	return time.Hour
+}
+
+// Retention is how long keys of the given granularity are kept.
+func Retention(granularity string) time.Duration {
+	if granularity == Day {
+		return DayRetention
+	}
+	return HourRetention
+}
+
+// Key is the HyperLogLog of a page, or of the whole site when pageURL is empty, for the
+// bucket containing ts.
+func Key(granularity, pageURL string, ts time.Time) string {
+	if pageURL == "" {
+		pageURL = anyPage
+	}
+	bucket := ts.UTC().Truncate(Width(granularity))
+	return fmt.Sprintf("uniques:%s:%d:%s", granularity, bucket.Unix(), pageURL)
+}
+
+// Keys returns the fewest keys covering [start, end) rounded out to whole hours: whole days
+// use their daily key, the hours around them their hourly keys. Hours whose key has expired
+// are widened to their whole day, and days whose key has expired are cut off the start. It
+// also returns the rounded range actually covered. It fails with ErrExpired when every day
+// has expired and with ErrTooManyKeys past MaxKeys.
+func Keys(pageURL string, start, end, now time.Time) ([]string, time.Time, time.Time, error) {
+	start = start.UTC().Truncate(time.Hour)
+	if rounded := end.UTC().Truncate(time.Hour); rounded.Before(end) {
+		end = rounded.Add(time.Hour)
+	} else {
+		end = rounded
+	}
+
+	// a daily key expires DayRetention after its day ends
+	if dayCutoff := now.Add(-DayRetention).UTC().Truncate(24 * time.Hour); start.Before(dayCutoff) {
+		if !end.After(dayCutoff) {
+			return nil, time.Time{}, time.Time{}, ErrExpired
+		}
+		start = dayCutoff
+	}
+
+	var keys []string
+	coveredStart, coveredEnd := start, end
+	hourCutoff := now.Add(-HourRetention).UTC().Truncate(time.Hour)
+
+	for t := start; t.Before(end); {
+		day := t.Truncate(24 * time.Hour)
+
+		switch {
+		case t.Equal(day) && !t.Add(24*time.Hour).After(end):
+			keys = append(keys, Key(Day, pageURL, t))
+			t = t.Add(24 * time.Hour)
+		case t.Before(hourCutoff):
+			keys = append(keys, Key(Day, pageURL, day))
+			coveredStart = minTime(coveredStart, day)
+			t = day.Add(24 * time.Hour)
+			coveredEnd = maxTime(coveredEnd, t)
+		default:
+			keys = append(keys, Key(Hour, pageURL, t))
+			t = t.Add(time.Hour)
+		}
+		if len(keys) > MaxKeys {
+			return nil, time.Time{}, time.Time{}, ErrTooManyKeys
+		}
+	}
+	return keys, coveredStart, coveredEnd, nil
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
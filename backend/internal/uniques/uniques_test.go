@@ -0,0 +1,170 @@
+package uniques
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestKeys(t *testing.T) {
+	defer func(hour, day time.Duration) { HourRetention, DayRetention = hour, day }(HourRetention, DayRetention)
+	HourRetention = 7 * 24 * time.Hour
+	DayRetention = 400 * 24 * time.Hour
+
+	at := func(value string) time.Time {
+		ts, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ts
+	}
+	hour := func(value string) string { return Key(Hour, "/home", at(value)) }
+	day := func(value string) string { return Key(Day, "/home", at(value)) }
+
+	// hourly keys before 2025-11-03T12:00Z have expired, daily keys before 2024-10-06
+	now := at("2025-11-10T12:00:00Z")
+
+	tests := []struct {
+		name       string
+		start, end time.Time
+		want       []string
+		wantStart  string
+		wantEnd    string
+		wantErr    error
+	}{
+		{
+			name:      "within one hour rounds out to it",
+			start:     at("2025-11-10T10:15:00Z"),
+			end:       at("2025-11-10T10:45:00Z"),
+			want:      []string{hour("2025-11-10T10:00:00Z")},
+			wantStart: "2025-11-10T10:00:00Z",
+			wantEnd:   "2025-11-10T11:00:00Z",
+		},
+		{
+			name:      "end on the hour is exclusive",
+			start:     at("2025-11-10T10:00:00Z"),
+			end:       at("2025-11-10T12:00:00Z"),
+			want:      []string{hour("2025-11-10T10:00:00Z"), hour("2025-11-10T11:00:00Z")},
+			wantStart: "2025-11-10T10:00:00Z",
+			wantEnd:   "2025-11-10T12:00:00Z",
+		},
+		{
+			name:      "whole day uses the daily key",
+			start:     at("2025-11-08T00:00:00Z"),
+			end:       at("2025-11-09T00:00:00Z"),
+			want:      []string{day("2025-11-08T00:00:00Z")},
+			wantStart: "2025-11-08T00:00:00Z",
+			wantEnd:   "2025-11-09T00:00:00Z",
+		},
+		{
+			name:  "hours around a whole day",
+			start: at("2025-11-07T22:00:00Z"),
+			end:   at("2025-11-09T02:00:00Z"),
+			want: []string{
+				hour("2025-11-07T22:00:00Z"),
+				hour("2025-11-07T23:00:00Z"),
+				day("2025-11-08T00:00:00Z"),
+				hour("2025-11-09T00:00:00Z"),
+				hour("2025-11-09T01:00:00Z"),
+			},
+			wantStart: "2025-11-07T22:00:00Z",
+			wantEnd:   "2025-11-09T02:00:00Z",
+		},
+		{
+			name:      "expired hours widen to their day",
+			start:     at("2025-11-01T05:00:00Z"),
+			end:       at("2025-11-01T07:00:00Z"),
+			want:      []string{day("2025-11-01T00:00:00Z")},
+			wantStart: "2025-11-01T00:00:00Z",
+			wantEnd:   "2025-11-02T00:00:00Z",
+		},
+		{
+			name:      "day straddling the cutoff is read whole",
+			start:     at("2025-11-03T10:00:00Z"),
+			end:       at("2025-11-03T14:00:00Z"),
+			want:      []string{day("2025-11-03T00:00:00Z")},
+			wantStart: "2025-11-03T00:00:00Z",
+			wantEnd:   "2025-11-04T00:00:00Z",
+		},
+		{
+			name:  "expired hours, then whole days, then live hours",
+			start: at("2025-11-02T20:00:00Z"),
+			end:   at("2025-11-04T01:30:00Z"),
+			want: []string{
+				day("2025-11-02T00:00:00Z"),
+				day("2025-11-03T00:00:00Z"),
+				hour("2025-11-04T00:00:00Z"),
+				hour("2025-11-04T01:00:00Z"),
+			},
+			wantStart: "2025-11-02T00:00:00Z",
+			wantEnd:   "2025-11-04T02:00:00Z",
+		},
+		{
+			name:      "buckets are UTC whatever the input zone",
+			start:     at("2025-11-10T05:30:00+05:30"),
+			end:       at("2025-11-10T06:30:00+05:30"),
+			want:      []string{hour("2025-11-10T00:00:00Z")},
+			wantStart: "2025-11-10T00:00:00Z",
+			wantEnd:   "2025-11-10T01:00:00Z",
+		},
+		{
+			name:  "expired days are cut off the start",
+			start: at("2024-10-04T05:00:00Z"),
+			end:   at("2024-10-08T00:00:00Z"),
+			want: []string{
+				day("2024-10-06T00:00:00Z"),
+				day("2024-10-07T00:00:00Z"),
+			},
+			wantStart: "2024-10-06T00:00:00Z",
+			wantEnd:   "2024-10-08T00:00:00Z",
+		},
+		{
+			name:    "every day expired",
+			start:   at("2024-01-01T00:00:00Z"),
+			end:     at("2024-10-06T00:00:00Z"),
+			wantErr: ErrExpired,
+		},
+		{
+			name:    "too many keys",
+			start:   at("2025-01-01T00:00:00Z"),
+			end:     at("2025-11-01T00:00:00Z"),
+			wantErr: ErrTooManyKeys,
+		},
+		{
+			name:      "empty range",
+			start:     at("2025-11-10T10:00:00Z"),
+			end:       at("2025-11-10T10:00:00Z"),
+			want:      nil,
+			wantStart: "2025-11-10T10:00:00Z",
+			wantEnd:   "2025-11-10T10:00:00Z",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, start, end, err := Keys("/home", tt.start, tt.end, now)
+			if err != tt.wantErr {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if !slices.Equal(keys, tt.want) {
+				t.Errorf("keys = %v, want %v", keys, tt.want)
+			}
+			if !start.Equal(at(tt.wantStart)) || !end.Equal(at(tt.wantEnd)) {
+				t.Errorf("covered [%s, %s), want [%s, %s)", start, end, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestKeySitewide(t *testing.T) {
+	ts := time.Date(2025, 11, 10, 10, 42, 0, 0, time.UTC)
+	if got, want := Key(Hour, "", ts), "uniques:hour:1762768800:*"; got != want {
+		t.Errorf("Key(Hour, \"\") = %q, want %q", got, want)
+	}
+	if got, want := Key(Day, "", ts), "uniques:day:1762732800:*"; got != want {
+		t.Errorf("Key(Day, \"\") = %q, want %q", got, want)
+	}
+}
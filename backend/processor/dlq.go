@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"event-analytics/internal/config"
+	"event-analytics/internal/event"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/segmentio/kafka-go"
+)
+
+// Headers added to every dead-lettered message. The original headers are kept so a redrive
+// restores the message exactly as ingestion published it.
+const (
+	headerDLQError             = "x-dlq-error"
+	headerDLQOriginalTopic     = "x-dlq-original-topic"
+	headerDLQOriginalPartition = "x-dlq-original-partition"
+	headerDLQOriginalOffset    = "x-dlq-original-offset"
+	headerDLQAttempts          = "x-dlq-attempts"
+	headerDLQFailedAt          = "x-dlq-failed-at"
+
+	headerDLQPrefix = "x-dlq-"
+
+	// the redrive CLI tracks its position in the dead-letter topic with this group
+	dlqRedriveGroup = "click-processor-dlq-redrive"
+)
+
+var (
+	dlqTopic    = config.GetEnv("KAFKA_DLQ_TOPIC", "clicks-dlq")
+	dlqProducer *kafka.Writer
+
+	deadLetteredTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "processor_dead_lettered_total",
+		Help: "Messages sent to the dead-letter topic, by reason.",
+	}, []string{"reason"})
+)
+
+// deadLetter is a message that can't be stored and the reason why.
+type deadLetter struct {
+	msg      kafka.Message
+	reason   string // "decode" or "store"
+	err      error
+	attempts int
+}
+
+func createProducer(kafkaBroker, topic string) *kafka.Writer {
+	return &kafka.Writer{
+		Addr:                   kafka.TCP(kafkaBroker),
+		Topic:                  topic,
+		Balancer:               &kafka.Hash{},
+		RequiredAcks:           kafka.RequireAll,
+		AllowAutoTopicCreation: true,
+		BatchTimeout:           10 * time.Millisecond,
+	}
+}
+
+func (d deadLetter) message(failedAt time.Time) kafka.Message {
+	headers := make([]kafka.Header, 0, len(d.msg.Headers)+6)
+	headers = append(headers, d.msg.Headers...)
+	headers = append(headers,
+		kafka.Header{Key: headerDLQError, Value: []byte(d.err.Error())},
+		kafka.Header{Key: headerDLQOriginalTopic, Value: []byte(d.msg.Topic)},
+		kafka.Header{Key: headerDLQOriginalPartition, Value: []byte(strconv.Itoa(d.msg.Partition))},
+		kafka.Header{Key: headerDLQOriginalOffset, Value: []byte(strconv.FormatInt(d.msg.Offset, 10))},
+		kafka.Header{Key: headerDLQAttempts, Value: []byte(strconv.Itoa(d.attempts))},
+		kafka.Header{Key: headerDLQFailedAt, Value: []byte(failedAt.UTC().Format(time.RFC3339))},
+	)
+	return kafka.Message{Key: d.msg.Key, Value: d.msg.Value, Headers: headers}
+}
+
+// publishDeadLetters writes the failed messages of a batch to the dead-letter topic. The batch
+// must not be committed unless this succeeds, or the messages would be lost.
+func publishDeadLetters(ctx context.Context, letters []deadLetter) error {
+	if len(letters) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	messages := make([]kafka.Message, len(letters))
+	for i, letter := range letters {
+		messages[i] = letter.message(now)
+		log.Printf("Dead-lettering partition %d offset %d after %d attempts: %v",
+			letter.msg.Partition, letter.msg.Offset, letter.attempts, letter.err)
+	}
+
+	writeCtx, cancel := context.WithTimeout(ctx, time.Second*10)
+	defer cancel()
+
+	if err := dlqProducer.WriteMessages(writeCtx, messages...); err != nil {
+		return fmt.Errorf("write to dead-letter topic %s: %w", dlqTopic, err)
+	}
+	for _, letter := range letters {
+		deadLetteredTotal.WithLabelValues(letter.reason).Inc()
+	}
+	return nil
+}
+
+// runDLQ implements `processor dlq inspect|redrive`. Both read the dead-letter topic from
+// where the last redrive stopped, so inspect shows exactly what redrive would send.
+func runDLQ(args []string) int {
+	usage := func() {
+		fmt.Fprintf(os.Stderr, "usage: processor dlq inspect|redrive [-limit N] [-wait DURATION]\n")
+	}
+	if len(args) == 0 {
+		usage()
+		return 2
+	}
+
+	command := args[0]
+	flags := flag.NewFlagSet("dlq "+command, flag.ContinueOnError)
+	limit := flags.Int("limit", 0, "stop after N messages, 0 for all")
+	wait := flags.Duration("wait", 10*time.Second, "stop once no message arrived for this long")
+	if err := flags.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     []string{kafkaBroker},
+		Topic:       dlqTopic,
+		GroupID:     dlqRedriveGroup,
+		StartOffset: kafka.FirstOffset,
+	})
+	defer reader.Close()
+
+	var handle func(kafka.Message) error
+	switch command {
+	case "inspect":
+		handle = func(msg kafka.Message) error {
+			printDeadLetter(msg)
+			return nil
+		}
+	case "redrive":
+		writer := createProducer(kafkaBroker, kafkaTopic)
+		defer writer.Close()
+
+		handle = func(msg kafka.Message) error {
+			writeCtx, cancel := context.WithTimeout(ctx, time.Second*10)
+			defer cancel()
+
+			if err := writer.WriteMessages(writeCtx, redriveMessage(msg)); err != nil {
+				return fmt.Errorf("write to %s: %w", kafkaTopic, err)
+			}
+			return reader.CommitMessages(context.Background(), msg)
+		}
+	default:
+		usage()
+		return 2
+	}
+
+	count := 0
+	for *limit == 0 || count < *limit {
+		fetchCtx, cancel := context.WithTimeout(ctx, *wait)
+		msg, err := reader.FetchMessage(fetchCtx)
+		cancel()
+		if err != nil {
+			if ctx.Err() == nil && fetchCtx.Err() == nil {
+				log.Printf("Can't read the dead-letter topic %s: %v", dlqTopic, err)
+				return 1
+			}
+			break // interrupted, or the topic is drained
+		}
+
+		if err := handle(msg); err != nil {
+			log.Printf("Stopped at partition %d offset %d: %v", msg.Partition, msg.Offset, err)
+			return 1
+		}
+		count++
+	}
+
+	if command == "redrive" {
+		fmt.Printf("Redrove %d messages from %s to %s\n", count, dlqTopic, kafkaTopic)
+	} else {
+		fmt.Printf("%d messages waiting in %s\n", count, dlqTopic)
+	}
+	return 0
+}
+
+// redriveMessage restores a dead-lettered message to what ingestion originally published.
+func redriveMessage(msg kafka.Message) kafka.Message {
+	headers := make([]kafka.Header, 0, len(msg.Headers))
+	for _, h := range msg.Headers {
+		if !strings.HasPrefix(h.Key, headerDLQPrefix) {
+			headers = append(headers, h)
+		}
+	}
+	return kafka.Message{Key: msg.Key, Value: msg.Value, Headers: headers}
+}
+
+func printDeadLetter(msg kafka.Message) {
+	header := func(key string) string {
+		for _, h := range msg.Headers {
+			if h.Key == key {
+				return string(h.Value)
+			}
+		}
+		return ""
+	}
+
+	fmt.Printf("partition=%d offset=%d key=%s attempts=%s failed_at=%s original=%s/%s/%s\n",
+		msg.Partition, msg.Offset, msg.Key,
+		header(headerDLQAttempts), header(headerDLQFailedAt),
+		header(headerDLQOriginalTopic), header(headerDLQOriginalPartition), header(headerDLQOriginalOffset))
+	fmt.Printf("  error: %s\n", header(headerDLQError))
+
+	// show decodable events as JSON whatever their wire format, raw bytes otherwise
+	if clickEvent, err := event.Decode(redriveMessage(msg)); err == nil {
+		body, _ := json.Marshal(clickEvent)
+		fmt.Printf("  event: %s\n", body)
+	} else {
+		fmt.Printf("  value: %q\n", msg.Value)
+	}
+}
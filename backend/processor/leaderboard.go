@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"event-analytics/internal/event"
+	"event-analytics/internal/leaderboard"
+)
+
+// updateLeaderboards adds newly inserted events to the per-minute top pages and top users
+// sorted sets, once under every event type and once under their own. Only inserted events
+// are passed in, so a replayed batch adds nothing. Events older than the horizon are skipped,
+// nothing reads their buckets from Redis anymore.
+func updateLeaderboards(ctx context.Context, events []event.ClickEvent) {
+	if len(events) == 0 {
+		return
+	}
+
+	now := time.Now()
+	increments := make(map[string]map[string]float64)
+	add := func(key, member string) {
+		if increments[key] == nil {
+			increments[key] = make(map[string]float64)
+		}
+		increments[key][member]++
+	}
+
+	for _, e := range events {
+		if !leaderboard.InHorizon(e.TimeStamp, now) {
+			continue
+		}
+		types := []string{""}
+		if e.EventType != "" {
+			types = append(types, e.EventType)
+		}
+		for _, eventType := range types {
+			add(leaderboard.Key(leaderboard.Pages, eventType, e.TimeStamp), e.PageUrl)
+			add(leaderboard.Key(leaderboard.Users, eventType, e.TimeStamp), e.UserId)
+		}
+	}
+	if len(increments) == 0 {
+		return
+	}
+
+	pipe := rdb.Pipeline()
+	for key, members := range increments {
+		for member, n := range members {
+			pipe.ZIncrBy(ctx, key, n, member)
+		}
+		pipe.Expire(ctx, key, leaderboard.TTL())
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("error: Can't update the leaderboards: %v", err)
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"event-analytics/internal/event"
+	"event-analytics/internal/live"
+)
+
+// publishCounts publishes what the newly inserted events added to page_clicks, for the
+// analytics service to stream to live subscribers. Pub/sub doesn't keep messages, a subscriber
+// that isn't connected when a batch is published misses it.
+func publishCounts(ctx context.Context, events []event.ClickEvent) {
+	if len(events) == 0 {
+		return
+	}
+
+	counts := pageCounts(events)
+	batch := live.Batch{Changes: make([]live.Change, 0, len(counts)), PublishedAt: time.Now()}
+	for key, n := range counts {
+		batch.Changes = append(batch.Changes, live.Change{
+			UserId:    key.UserId,
+			PageUrl:   key.PageUrl,
+			EventType: key.EventType,
+			Delta:     int64(n),
+		})
+	}
+
+	payload, err := json.Marshal(batch)
+	if err != nil {
+		log.Printf("error: Can't encode count changes: %v", err)
+		return
+	}
+	if err := rdb.Publish(ctx, live.Channel, payload).Err(); err != nil {
+		log.Printf("error: Can't publish count changes: %v", err)
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestShutdownDeadline(t *testing.T) {
+	ctx, stop := context.WithCancel(context.Background())
+	drainCtx, cancel := shutdownDeadline(ctx, 50*time.Millisecond)
+	defer cancel()
+
+	stop()
+	if drainCtx.Err() != nil {
+		t.Fatal("drain context canceled with the signal, want it to outlive it")
+	}
+
+	select {
+	case <-drainCtx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("drain context still running long after the timeout")
+	}
+
+	// without a signal it only ends when released
+	drainCtx, cancel = shutdownDeadline(context.Background(), time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	if drainCtx.Err() != nil {
+		t.Error("drain context canceled without a signal")
+	}
+	cancel()
+	if drainCtx.Err() == nil {
+		t.Error("drain context still running after cancel")
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"event-analytics/internal/event"
+
+	"github.com/lib/pq"
+)
+
+// storeFirstSeen records when each user was first seen doing each event type, and doing
+// anything under an empty event_type. Keeping the earliest time makes a replayed or out of order
+// batch harmless.
+func storeFirstSeen(ctx context.Context, tx *sql.Tx, events []event.ClickEvent) error {
+	type firstSeenKey struct {
+		UserId    string
+		EventType string
+	}
+	firstSeen := make(map[firstSeenKey]time.Time)
+	for _, e := range events {
+		for _, key := range []firstSeenKey{{e.UserId, e.EventType}, {e.UserId, ""}} {
+			if seen, ok := firstSeen[key]; !ok || e.TimeStamp.Before(seen) {
+				firstSeen[key] = e.TimeStamp
+			}
+		}
+	}
+	if len(firstSeen) == 0 {
+		return nil
+	}
+
+	// (user_id, event_type)
+	keys := sortedKeys(firstSeen, func(a, b firstSeenKey) int {
+		return cmp.Or(strings.Compare(a.UserId, b.UserId), strings.Compare(a.EventType, b.EventType))
+	})
+
+	users := make([]string, 0, len(firstSeen))
+	types := make([]string, 0, len(firstSeen))
+	stamps := make([]string, 0, len(firstSeen))
+	for _, key := range keys {
+		seen := firstSeen[key]
+		users = append(users, key.UserId)
+		types = append(types, key.EventType)
+		stamps = append(stamps, seen.Format(time.RFC3339Nano))
+	}
+
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO user_first_seen (user_id, event_type, first_seen)
+		SELECT user_id, event_type, ts::timestamptz
+		FROM unnest($1::text[], $2::text[], $3::text[]) AS f(user_id, event_type, ts)
+		ON CONFLICT (user_id, event_type) DO UPDATE SET first_seen = EXCLUDED.first_seen
+		WHERE EXCLUDED.first_seen < user_first_seen.first_seen`,
+		pq.Array(users), pq.Array(types), pq.Array(stamps))
+	if err != nil {
+		return fmt.Errorf("store user_first_seen: %w", err)
+	}
+	return nil
+}
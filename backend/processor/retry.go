@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"time"
+
+	"event-analytics/internal/config"
+
+	"github.com/lib/pq"
+)
+
+var (
+	// retries back off exponentially from retryDelay up to retryMaxDelay
+	retryDelay    = config.GetDuration("PROCESSOR_RETRY_DELAY", 500*time.Millisecond)
+	retryMaxDelay = config.GetDuration("PROCESSOR_RETRY_MAX_DELAY", 30*time.Second)
+	// attempts before an error that is neither clearly transient nor permanent dead-letters
+	maxAttempts = config.GetInt("PROCESSOR_MAX_ATTEMPTS", 5)
+)
+
+type errorClass int
+
+const (
+	// errTransient is an outage of a dependency. It says nothing about the message, so it is
+	// retried until the dependency recovers instead of dead-lettering healthy events.
+	errTransient errorClass = iota
+	// errUnknown is retried up to maxAttempts, then treated as permanent.
+	errUnknown
+	// errPermanent means the message itself can't be stored, retrying won't help.
+	errPermanent
+)
+
+func classify(err error) errorClass {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code.Class() {
+		case "22", "23", "54": // data exception, integrity violation, program limit exceeded
+			return errPermanent
+		case "08", "40", "53", "57", "58": // connection, rollback, resources, operator, system
+			return errTransient
+		}
+		return errUnknown
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) ||
+		errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, context.DeadlineExceeded) {
+		return errTransient
+	}
+	return errUnknown
+}
+
+// backoff returns the delay before the given retry, doubling from retryDelay.
+func backoff(attempt int) time.Duration {
+	delay := retryDelay
+	for i := 1; i < attempt && delay < retryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, retryMaxDelay)
+}
+
+// withRetry runs fn until it succeeds, fails permanently or ctx is done, and returns the
+// number of attempts made with the last error. ctx only cuts the waits between attempts short.
+func withRetry(ctx context.Context, what string, fn func() error) (int, error) {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return attempt, nil
+		}
+
+		class := classify(err)
+		if class == errPermanent || (class == errUnknown && attempt >= maxAttempts) {
+			return attempt, err
+		}
+
+		delay := backoff(attempt)
+		log.Printf("Failed to %s (attempt %d), retrying in %s: %v", what, attempt, delay, err)
+		select {
+		case <-ctx.Done():
+			return attempt, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
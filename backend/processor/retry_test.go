@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want errorClass
+	}{
+		{"invalid text representation", &pq.Error{Code: "22P02"}, errPermanent},
+		{"value too long", &pq.Error{Code: "22001"}, errPermanent},
+		{"not null violation", &pq.Error{Code: "23502"}, errPermanent},
+		{"program limit exceeded", &pq.Error{Code: "54000"}, errPermanent},
+		{"connection failure", &pq.Error{Code: "08006"}, errTransient},
+		{"serialization failure", &pq.Error{Code: "40001"}, errTransient},
+		{"deadlock", &pq.Error{Code: "40P01"}, errTransient},
+		{"too many connections", &pq.Error{Code: "53300"}, errTransient},
+		{"admin shutdown", &pq.Error{Code: "57P01"}, errTransient},
+		{"io error", &pq.Error{Code: "58030"}, errTransient},
+		{"undefined table", &pq.Error{Code: "42P01"}, errUnknown},
+		{"wrapped pq error", fmt.Errorf("insert batch: %w", &pq.Error{Code: "23505"}), errPermanent},
+		{"network error", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, errTransient},
+		{"bad connection", driver.ErrBadConn, errTransient},
+		{"eof", io.EOF, errTransient},
+		{"unexpected eof", fmt.Errorf("read: %w", io.ErrUnexpectedEOF), errTransient},
+		{"deadline exceeded", context.DeadlineExceeded, errTransient},
+		{"anything else", errors.New("boom"), errUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classify(tt.err); got != tt.want {
+				t.Errorf("classify(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	defer func(delay, maxDelay time.Duration) { retryDelay, retryMaxDelay = delay, maxDelay }(retryDelay, retryMaxDelay)
+	retryDelay = 500 * time.Millisecond
+	retryMaxDelay = 30 * time.Second
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 500 * time.Millisecond},
+		{2, time.Second},
+		{3, 2 * time.Second},
+		{7, 30 * time.Second}, // 32s capped
+		{8, 30 * time.Second},
+		{1000, 30 * time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := backoff(tt.attempt); got != tt.want {
+			t.Errorf("backoff(%d) = %s, want %s", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	defer func(delay, maxDelay time.Duration, attempts int) {
+		retryDelay, retryMaxDelay, maxAttempts = delay, maxDelay, attempts
+	}(retryDelay, retryMaxDelay, maxAttempts)
+	retryDelay = time.Microsecond
+	retryMaxDelay = time.Microsecond
+	maxAttempts = 3
+
+	transient := &pq.Error{Code: "08006"}
+	permanent := &pq.Error{Code: "23502"}
+	unknown := errors.New("boom")
+
+	tests := []struct {
+		name         string
+		errs         []error // returned by successive attempts, nil after they run out
+		wantAttempts int
+		wantErr      error
+	}{
+		{"succeeds at once", nil, 1, nil},
+		{"permanent fails at once", []error{permanent}, 1, permanent},
+		{"unknown gives up after maxAttempts", []error{unknown, unknown, unknown, unknown}, 3, unknown},
+		{"transient retried past maxAttempts", []error{transient, transient, transient, transient}, 5, nil},
+		{"permanent after transient", []error{transient, permanent}, 2, permanent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			attempts, err := withRetry(context.Background(), "test", func() error {
+				calls++
+				if calls <= len(tt.errs) {
+					return tt.errs[calls-1]
+				}
+				return nil
+			})
+			if attempts != tt.wantAttempts || attempts != calls {
+				t.Errorf("attempts = %d (%d calls), want %d", attempts, calls, tt.wantAttempts)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("canceled context stops transient retries", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		retryDelay, retryMaxDelay = time.Hour, time.Hour
+
+		attempts, err := withRetry(ctx, "test", func() error { return transient })
+		if attempts != 1 || !errors.Is(err, context.Canceled) {
+			t.Errorf("withRetry = %d, %v, want 1, %v", attempts, err, context.Canceled)
+		}
+	})
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"event-analytics/internal/config"
+	"event-analytics/internal/event"
+	"event-analytics/internal/rollup"
+
+	"github.com/lib/pq"
+)
+
+var rollupCleanupInterval = config.GetDuration("ROLLUP_CLEANUP_INTERVAL", 10*time.Minute)
+
+type rollupKey struct {
+	Bucket    time.Time
+	PageUrl   string
+	UserId    string
+	EventType string
+}
+
+// storeRollup adds the newly inserted events to a rollup table with one upsert of pre-summed counts.
+// Only events that click_events actually inserted are passed in, so a replayed batch adds
+// nothing.
+func storeRollup(ctx context.Context, tx *sql.Tx, r rollup.Table, events []event.ClickEvent, now time.Time) error {
+	counts := make(map[rollupKey]int64)
+	for _, e := range events {
+		bucket := r.Bucket(e.TimeStamp)
+		if r.Expired(bucket, now) {
+			continue
+		}
+		counts[rollupKey{Bucket: bucket, PageUrl: e.PageUrl, UserId: e.UserId, EventType: e.EventType}]++
+	}
+	if len(counts) == 0 {
+		return nil
+	}
+
+	// (bucket, page_url, user_id, event_type)
+	keys := sortedKeys(counts, func(a, b rollupKey) int {
+		return cmp.Or(a.Bucket.Compare(b.Bucket), strings.Compare(a.PageUrl, b.PageUrl),
+			strings.Compare(a.UserId, b.UserId), strings.Compare(a.EventType, b.EventType))
+	})
+
+	buckets := make([]string, 0, len(counts))
+	pages := make([]string, 0, len(counts))
+	users := make([]string, 0, len(counts))
+	types := make([]string, 0, len(counts))
+	aggCounts := make([]int64, 0, len(counts))
+	for _, key := range keys {
+		n := counts[key]
+		buckets = append(buckets, key.Bucket.Format(time.RFC3339))
+		pages = append(pages, key.PageUrl)
+		users = append(users, key.UserId)
+		types = append(types, key.EventType)
+		aggCounts = append(aggCounts, n)
+	}
+
+	_, err := tx.ExecContext(ctx, fmt.Sprintf(`
+		INSERT INTO %[1]s (bucket, page_url, user_id, event_type, click_count)
+		SELECT b::timestamptz, page_url, user_id, event_type, n
+		FROM unnest($1::text[], $2::text[], $3::text[], $4::text[], $5::bigint[])
+			AS r(b, page_url, user_id, event_type, n)
+		ON CONFLICT (bucket, page_url, user_id, event_type)
+		DO UPDATE SET click_count = %[1]s.click_count + EXCLUDED.click_count`, r.Name),
+		pq.Array(buckets), pq.Array(pages), pq.Array(users), pq.Array(types), pq.Array(aggCounts))
+	return err
+}
+
+// cleanupRollups deletes buckets past their retention every interval until ctx is done.
+func cleanupRollups(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for _, r := range rollup.Tables {
+			if r.Retention <= 0 {
+				continue
+			}
+			cutoff := r.Cutoff(time.Now())
+			result, err := db.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE bucket < $1`, r.Name), cutoff)
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Printf("Failed to clean up %s: %v", r.Name, err)
+				}
+				continue
+			}
+			if n, _ := result.RowsAffected(); n > 0 {
+				log.Printf("Deleted %d %s rows older than %s", n, r.Name, cutoff.Format(time.RFC3339))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
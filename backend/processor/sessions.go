@@ -0,0 +1,454 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"event-analytics/internal/config"
+	"event-analytics/internal/event"
+
+	"github.com/lib/pq"
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	// a user's next event more than sessionGap after their last one starts a new session
+	sessionGap           = config.GetDuration("SESSION_GAP", 30*time.Minute)
+	sessionSweepInterval = config.GetDuration("SESSION_SWEEP_INTERVAL", 30*time.Second)
+	// open session state expires after this even if no sweeper closes it, the sweeper then
+	// ends its row from the table
+	sessionStateTTL = config.GetDuration("SESSION_STATE_TTL", 24*time.Hour)
+)
+
+const (
+	// users with an open session, scored by the wall-clock time of their last event
+	activeSessionsKey = "sessions:active"
+	// closed sessions by id, kept until their row is written so a failed write isn't lost
+	closingSessionsKey = "sessions:closing"
+	sessionSweepBatch  = 500
+)
+
+func sessionKey(userID string) string      { return "session:" + userID }
+func sessionPagesKey(userID string) string { return "session:" + userID + ":pages" }
+
+// trackSession adds one event to its user's open session, closing it first when the event
+// is more than the gap after its last event. It returns the closed session, if any, and the
+// open one; both are empty for an event more than the gap before the session started, which
+// is too late to attribute. A closed session is also copied to the closing hash.
+//
+// KEYS: session hash, session pages set, active sessions zset, closing sessions hash
+// ARGV: user_id, event time ms, page_url, gap ms, id for a new session, now ms, state ttl s
+var trackSession = redis.NewScript(`
+local ts = tonumber(ARGV[2])
+local gap = tonumber(ARGV[4])
+local fields = {'id', 'start', 'last', 'entry', 'exit', 'events'}
+
+local closed = {}
+local last = tonumber(redis.call('HGET', KEYS[1], 'last'))
+if last then
+	if ts < tonumber(redis.call('HGET', KEYS[1], 'start')) - gap then
+		return {{}, {}}
+	end
+	if ts > last + gap then
+		closed = redis.call('HMGET', KEYS[1], unpack(fields))
+		closed[7] = tostring(redis.call('SCARD', KEYS[2]))
+		closed[8] = ARGV[1]
+		redis.call('HSET', KEYS[4], closed[1], cjson.encode(closed))
+		redis.call('DEL', KEYS[1], KEYS[2])
+		last = nil
+	end
+end
+
+if not last then
+	redis.call('HSET', KEYS[1], 'id', ARGV[5], 'start', ARGV[2], 'last', ARGV[2],
+		'entry', ARGV[3], 'exit', ARGV[3], 'events', 0)
+else
+	if ts >= last then
+		redis.call('HSET', KEYS[1], 'last', ARGV[2], 'exit', ARGV[3])
+	end
+	if ts < tonumber(redis.call('HGET', KEYS[1], 'start')) then
+		redis.call('HSET', KEYS[1], 'start', ARGV[2], 'entry', ARGV[3])
+	end
+end
+redis.call('HINCRBY', KEYS[1], 'events', 1)
+redis.call('SADD', KEYS[2], ARGV[3])
+redis.call('ZADD', KEYS[3], ARGV[6], ARGV[1])
+redis.call('EXPIRE', KEYS[1], ARGV[7])
+redis.call('EXPIRE', KEYS[2], ARGV[7])
+
+local open = redis.call('HMGET', KEYS[1], unpack(fields))
+open[7] = tostring(redis.call('SCARD', KEYS[2]))
+return {closed, open}
+`)
+
+// closeSession closes a user's session when it has been idle since before the cutoff, moves
+// it to the closing hash and returns it. Tracking and closing are both scripts, so a session
+// is closed exactly once even with several processors sweeping.
+//
+// KEYS: session hash, session pages set, active sessions zset, closing sessions hash
+// ARGV: user_id, cutoff ms
+var closeSession = redis.NewScript(`
+local score = tonumber(redis.call('ZSCORE', KEYS[3], ARGV[1]))
+if not score or score > tonumber(ARGV[2]) then
+	return {}
+end
+redis.call('ZREM', KEYS[3], ARGV[1])
+
+local s = redis.call('HMGET', KEYS[1], 'id', 'start', 'last', 'entry', 'exit', 'events')
+if not s[1] then
+	return {}
+end
+s[7] = tostring(redis.call('SCARD', KEYS[2]))
+s[8] = ARGV[1]
+redis.call('HSET', KEYS[4], s[1], cjson.encode(s))
+redis.call('DEL', KEYS[1], KEYS[2])
+return s
+`)
+
+type session struct {
+	ID         string
+	UserId     string
+	Start      int64 // unix ms
+	Last       int64
+	EntryPage  string
+	ExitPage   string
+	EventCount int
+	PageCount  int
+	Closed     bool
+}
+
+// parseSession reads the fields the scripts return, reporting false for an empty reply. The
+// closing hash holds the same fields, followed by the user_id, as a JSON array.
+func parseSession(userID string, reply interface{}, closed bool) (session, bool) {
+	values, ok := reply.([]interface{})
+	if !ok || len(values) < 7 || values[0] == nil {
+		return session{}, false
+	}
+
+	field := func(i int) string {
+		s, _ := values[i].(string)
+		return s
+	}
+	number := func(i int) int64 {
+		n, _ := strconv.ParseInt(field(i), 10, 64)
+		return n
+	}
+
+	return session{
+		ID:         field(0),
+		UserId:     userID,
+		Start:      number(1),
+		Last:       number(2),
+		EntryPage:  field(3),
+		ExitPage:   field(4),
+		EventCount: int(number(5)),
+		PageCount:  int(number(6)),
+		Closed:     closed,
+	}, true
+}
+
+func newSessionID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// updateSessions runs the newly inserted events through their users' sessions in time order,
+// then writes every session they started, extended or closed to the sessions table. Only
+// inserted events are passed in, so a replayed batch doesn't count twice.
+func updateSessions(ctx context.Context, events []event.ClickEvent) {
+	if len(events) == 0 {
+		return
+	}
+
+	ordered := append([]event.ClickEvent(nil), events...)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].TimeStamp.Before(ordered[j].TimeStamp) })
+
+	now := time.Now().UnixMilli()
+	pipe := rdb.Pipeline()
+	cmds := make([]*redis.Cmd, len(ordered))
+	for i, e := range ordered {
+		cmds[i] = trackSession.Eval(ctx, pipe,
+			[]string{sessionKey(e.UserId), sessionPagesKey(e.UserId), activeSessionsKey, closingSessionsKey},
+			e.UserId, e.TimeStamp.UnixMilli(), e.PageUrl, sessionGap.Milliseconds(), newSessionID(), now, int(sessionStateTTL.Seconds()))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("error: Can't update sessions: %v", err)
+		return
+	}
+
+	// the last state seen of each session is the newest
+	sessions := make(map[string]session)
+	for i, cmd := range cmds {
+		reply, ok := cmd.Val().([]interface{})
+		if !ok || len(reply) != 2 {
+			continue
+		}
+		if s, ok := parseSession(ordered[i].UserId, reply[0], true); ok {
+			sessions[s.ID] = s
+		}
+		if s, ok := parseSession(ordered[i].UserId, reply[1], false); ok {
+			sessions[s.ID] = s
+		}
+	}
+
+	if err := storeSessions(ctx, sessions); err != nil {
+		log.Printf("error: Can't store sessions: %v", err)
+	}
+}
+
+// storeSessions upserts sessions into the sessions table. A closed session is final, and an
+// open one only moves forward, so processors racing on the same session can't undo each other.
+// Closed sessions leave the closing hash only once written; open ones are still in Redis, a
+// failed write is made good by the next one.
+func storeSessions(ctx context.Context, sessions map[string]session) error {
+	if len(sessions) == 0 {
+		return nil
+	}
+
+	var (
+		ids, users, entries, exits []string
+		starts, lasts              []int64
+		closed                     []bool
+		eventCounts, pageCounts    []int64
+		closedIDs                  []string
+	)
+	// by session_id
+	for _, id := range sortedKeys(sessions, strings.Compare) {
+		s := sessions[id]
+		if s.Closed {
+			closedIDs = append(closedIDs, s.ID)
+		}
+		ids = append(ids, s.ID)
+		users = append(users, s.UserId)
+		starts = append(starts, s.Start)
+		lasts = append(lasts, s.Last)
+		closed = append(closed, s.Closed)
+		eventCounts = append(eventCounts, int64(s.EventCount))
+		pageCounts = append(pageCounts, int64(s.PageCount))
+		entries = append(entries, s.EntryPage)
+		exits = append(exits, s.ExitPage)
+	}
+
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO sessions (session_id, user_id, started_at, last_seen_at, ended_at, duration_ms,
+			event_count, page_count, entry_page, exit_page)
+		SELECT id, user_id, to_timestamp(s / 1000.0), to_timestamp(l / 1000.0),
+			CASE WHEN closed THEN to_timestamp(l / 1000.0) END, l - s, n, p, entry, exit
+		FROM unnest($1::text[], $2::text[], $3::bigint[], $4::bigint[], $5::bool[], $6::int[], $7::int[], $8::text[], $9::text[])
+			AS x(id, user_id, s, l, closed, n, p, entry, exit)
+		ON CONFLICT (session_id) DO UPDATE SET
+			started_at = EXCLUDED.started_at,
+			last_seen_at = EXCLUDED.last_seen_at,
+			ended_at = EXCLUDED.ended_at,
+			duration_ms = EXCLUDED.duration_ms,
+			event_count = EXCLUDED.event_count,
+			page_count = EXCLUDED.page_count,
+			entry_page = EXCLUDED.entry_page,
+			exit_page = EXCLUDED.exit_page
+		WHERE sessions.ended_at IS NULL AND EXCLUDED.event_count >= sessions.event_count`,
+		pq.Array(ids), pq.Array(users), pq.Array(starts), pq.Array(lasts), pq.Array(closed),
+		pq.Array(eventCounts), pq.Array(pageCounts), pq.Array(entries), pq.Array(exits))
+	if err != nil {
+		return fmt.Errorf("store sessions: %w", err)
+	}
+
+	if len(closedIDs) > 0 {
+		if err := rdb.HDel(ctx, closingSessionsKey, closedIDs...).Err(); err != nil {
+			// they are written again on the next sweep, which changes nothing
+			log.Printf("Failed to clear closed sessions: %v", err)
+		}
+	}
+	return nil
+}
+
+// storeClosingSessions writes the closed sessions still in the closing hash, those whose
+// write failed or whose processor stopped before writing them.
+func storeClosingSessions(ctx context.Context) {
+	var cursor uint64
+	for {
+		entries, next, err := rdb.HScan(ctx, closingSessionsKey, cursor, "", sessionSweepBatch).Result()
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("Failed to list closing sessions: %v", err)
+			}
+			return
+		}
+
+		sessions := make(map[string]session)
+		for i := 0; i+1 < len(entries); i += 2 {
+			var fields []string
+			if err := json.Unmarshal([]byte(entries[i+1]), &fields); err != nil || len(fields) < 8 {
+				log.Printf("Skipping malformed closing session %s: %v", entries[i], err)
+				continue
+			}
+			values := make([]interface{}, len(fields))
+			for j, f := range fields {
+				values[j] = f
+			}
+			if s, ok := parseSession(fields[7], values, true); ok {
+				sessions[s.ID] = s
+			}
+		}
+
+		if err := storeSessions(ctx, sessions); err != nil {
+			log.Printf("error: Can't store closed sessions: %v", err)
+			return
+		}
+		if next == 0 {
+			return
+		}
+		cursor = next
+	}
+}
+
+// closeOrphanedSessions ends the open rows whose Redis state is gone, which happens when it
+// expired while no processor was sweeping. They would otherwise count as open forever. Rows
+// whose session is still open in Redis, or closed and waiting in the closing hash, are left
+// to the sweep.
+func closeOrphanedSessions(ctx context.Context) {
+	cutoff := time.Now().Add(-sessionGap)
+	var afterSeen time.Time
+	afterID := ""
+
+	for {
+		rows, err := db.QueryContext(ctx, `
+			SELECT session_id, user_id, last_seen_at FROM sessions
+			WHERE ended_at IS NULL AND last_seen_at < $1 AND (last_seen_at, session_id) > ($2, $3)
+			ORDER BY last_seen_at, session_id
+			LIMIT $4`, cutoff, afterSeen, afterID, sessionSweepBatch)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("Failed to list open sessions: %v", err)
+			}
+			return
+		}
+
+		var ids, users []string
+		for rows.Next() {
+			var id, userID string
+			if err := rows.Scan(&id, &userID, &afterSeen); err != nil {
+				rows.Close()
+				log.Printf("Failed to read open sessions: %v", err)
+				return
+			}
+			ids = append(ids, id)
+			users = append(users, userID)
+			afterID = id
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			log.Printf("Failed to read open sessions: %v", err)
+			return
+		}
+		if len(ids) == 0 {
+			return
+		}
+
+		pipe := rdb.Pipeline()
+		openIDs := make([]*redis.StringCmd, len(ids))
+		closing := make([]*redis.BoolCmd, len(ids))
+		for i := range ids {
+			openIDs[i] = pipe.HGet(ctx, sessionKey(users[i]), "id")
+			closing[i] = pipe.HExists(ctx, closingSessionsKey, ids[i])
+		}
+		if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+			log.Printf("Failed to look up open sessions: %v", err)
+			return
+		}
+
+		var orphaned []string
+		for i, id := range ids {
+			if openIDs[i].Val() != id && !closing[i].Val() {
+				orphaned = append(orphaned, id)
+			}
+		}
+		if len(orphaned) > 0 {
+			// a session closed since the lookup already has its ended_at, keep that one
+			_, err := db.ExecContext(ctx, `
+				UPDATE sessions SET ended_at = last_seen_at
+				WHERE session_id = ANY($1) AND ended_at IS NULL`, pq.Array(orphaned))
+			if err != nil {
+				log.Printf("error: Can't end orphaned sessions: %v", err)
+				return
+			}
+			log.Printf("Ended %d sessions whose state had expired", len(orphaned))
+		}
+
+		if len(ids) < sessionSweepBatch {
+			return
+		}
+	}
+}
+
+// sweepSessions closes sessions that saw no event for the gap, retries writing closed
+// sessions that failed to store and ends the rows of sessions whose state expired, every
+// interval until ctx is done. Idleness is judged on wall-clock time rather than event time,
+// so a processor catching up on a backlog doesn't close sessions whose next events are
+// still queued.
+func sweepSessions(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		storeClosingSessions(ctx)
+		closeOrphanedSessions(ctx)
+
+		cutoff := time.Now().Add(-sessionGap).UnixMilli()
+		for {
+			users, err := rdb.ZRangeByScore(ctx, activeSessionsKey, &redis.ZRangeBy{
+				Min:   "-inf",
+				Max:   strconv.FormatInt(cutoff, 10),
+				Count: sessionSweepBatch,
+			}).Result()
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Printf("Failed to list idle sessions: %v", err)
+				}
+				break
+			}
+			if len(users) == 0 {
+				break
+			}
+
+			sessions := make(map[string]session)
+			failed := false
+			for _, userID := range users {
+				reply, err := closeSession.Run(ctx, rdb,
+					[]string{sessionKey(userID), sessionPagesKey(userID), activeSessionsKey, closingSessionsKey},
+					userID, cutoff).Result()
+				if err != nil {
+					log.Printf("Failed to close session of %s: %v", userID, err)
+					failed = true
+					continue
+				}
+				if s, ok := parseSession(userID, reply, true); ok {
+					sessions[s.ID] = s
+				}
+			}
+
+			// on failure they stay in the closing hash and are retried next tick
+			if err := storeSessions(ctx, sessions); err != nil {
+				log.Printf("error: Can't store closed sessions: %v", err)
+			}
+			// sessions that failed to close stay listed, retry them next tick
+			if failed || len(users) < sessionSweepBatch {
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"event-analytics/internal/cache"
+	"event-analytics/internal/event"
+	"event-analytics/internal/rollup"
+
+	"github.com/lib/pq"
+	"github.com/segmentio/kafka-go"
+)
+
+type pageKey struct {
+	UserId    string
+	PageUrl   string
+	EventType string
+}
+
+// decodedMessage is a message that decoded into an event.
+type decodedMessage struct {
+	msg   kafka.Message
+	event event.ClickEvent
+}
+
+// decodeBatch turns messages into events, dropping duplicate event_ids within the batch.
+// Messages that can't be decoded are returned as dead letters, retrying them won't help.
+func decodeBatch(messages []kafka.Message) ([]decodedMessage, []deadLetter) {
+	decoded := make([]decodedMessage, 0, len(messages))
+	var failed []deadLetter
+	seen := make(map[string]bool, len(messages))
+
+	for _, msg := range messages {
+		clickEvent, err := event.Decode(msg)
+		if err != nil {
+			failed = append(failed, deadLetter{msg: msg, reason: "decode", err: err, attempts: 1})
+			continue
+		}
+		if seen[clickEvent.EventId] {
+			continue
+		}
+		seen[clickEvent.EventId] = true
+		decoded = append(decoded, decodedMessage{msg: msg, event: clickEvent})
+	}
+	return decoded, failed
+}
+
+// storeBatch writes a batch of events in one transaction: a single multi-row insert into
+// click_events, then upserts that add the totals of the rows that insert actually added to
+// page_clicks, page_totals, user_totals and the time-bucketed rollups. A multi-row insert is used rather than COPY
+// because COPY can't skip existing event_ids, and RETURNING tells us exactly which events
+// are new, which keeps every aggregate correct when a batch is replayed. It returns the
+// newly inserted events.
+func storeBatch(ctx context.Context, events []event.ClickEvent) ([]event.ClickEvent, error) {
+	if len(events) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, len(events))
+	users := make([]string, len(events))
+	types := make([]string, len(events))
+	pages := make([]string, len(events))
+	stamps := make([]string, len(events))
+	properties := make([]string, len(events))
+	for i, e := range events {
+		ids[i] = e.EventId
+		users[i] = e.UserId
+		types[i] = e.EventType
+		pages[i] = e.PageUrl
+		stamps[i] = e.TimeStamp.Format(time.RFC3339Nano)
+		properties[i] = e.PropertiesJSON()
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		INSERT INTO click_events (event_id, user_id, event_type, page_url, time_stamp, properties)
+		SELECT id, user_id, event_type, page_url, ts::timestamptz, props::jsonb
+		FROM unnest($1::text[], $2::text[], $3::text[], $4::text[], $5::text[], $6::text[])
+			AS e(id, user_id, event_type, page_url, ts, props)
+		ON CONFLICT (event_id) DO NOTHING
+		RETURNING event_id, user_id, event_type, page_url, time_stamp`,
+		pq.Array(ids), pq.Array(users), pq.Array(types), pq.Array(pages), pq.Array(stamps), pq.Array(properties))
+	if err != nil {
+		return nil, fmt.Errorf("store raw events: %w", err)
+	}
+
+	var inserted []event.ClickEvent
+	for rows.Next() {
+		var e event.ClickEvent
+		if err := rows.Scan(&e.EventId, &e.UserId, &e.EventType, &e.PageUrl, &e.TimeStamp); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		inserted = append(inserted, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("store raw events: %w", err)
+	}
+
+	if counts := pageCounts(inserted); len(counts) > 0 {
+		aggUsers := make([]string, 0, len(counts))
+		aggPages := make([]string, 0, len(counts))
+		aggTypes := make([]string, 0, len(counts))
+		aggCounts := make([]int64, 0, len(counts))
+		// (user_id, page_url, event_type)
+		keys := sortedKeys(counts, func(a, b pageKey) int {
+			return cmp.Or(strings.Compare(a.UserId, b.UserId), strings.Compare(a.PageUrl, b.PageUrl), strings.Compare(a.EventType, b.EventType))
+		})
+		for _, key := range keys {
+			n := counts[key]
+			aggUsers = append(aggUsers, key.UserId)
+			aggPages = append(aggPages, key.PageUrl)
+			aggTypes = append(aggTypes, key.EventType)
+			aggCounts = append(aggCounts, int64(n))
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO page_clicks (user_id, page_url, event_type, click_count)
+			SELECT * FROM unnest($1::text[], $2::text[], $3::text[], $4::int[])
+			ON CONFLICT (user_id, page_url, event_type) DO UPDATE SET click_count = page_clicks.click_count + EXCLUDED.click_count`,
+			pq.Array(aggUsers), pq.Array(aggPages), pq.Array(aggTypes), pq.Array(aggCounts))
+		if err != nil {
+			return nil, fmt.Errorf("store aggregated events: %w", err)
+		}
+	}
+
+	if err := storeTotals(ctx, tx, inserted); err != nil {
+		return nil, err
+	}
+
+	if err := storeFirstSeen(ctx, tx, inserted); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for _, r := range rollup.Tables {
+		if err := storeRollup(ctx, tx, r, inserted, now); err != nil {
+			return nil, fmt.Errorf("store %s: %w", r.Name, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return inserted, nil
+}
+
+// sortedKeys returns the keys of m ordered by compare. Multi-row upserts iterate it with
+// compare following the table's primary key: workers upsert the same rows concurrently, and
+// taking the row locks in one shared order keeps them from deadlocking.
+func sortedKeys[K comparable, V any](m map[K]V, compare func(a, b K) int) []K {
+	keys := make([]K, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, compare)
+	return keys
+}
+
+// pageCounts sums events per (user_id, page_url, event_type).
+func pageCounts(events []event.ClickEvent) map[pageKey]int {
+	counts := make(map[pageKey]int)
+	for _, e := range events {
+		counts[pageKey{UserId: e.UserId, PageUrl: e.PageUrl, EventType: e.EventType}]++
+	}
+	return counts
+}
+
+// storeTotals adds the newly inserted events to the per page and per user totals.
+func storeTotals(ctx context.Context, tx *sql.Tx, events []event.ClickEvent) error {
+	type totalKey struct {
+		Key       string
+		EventType string
+	}
+	pageTotals := make(map[totalKey]int64)
+	userTotals := make(map[totalKey]int64)
+	for _, e := range events {
+		pageTotals[totalKey{e.PageUrl, e.EventType}]++
+		userTotals[totalKey{e.UserId, e.EventType}]++
+	}
+
+	for _, totals := range []struct {
+		table, column string
+		counts        map[totalKey]int64
+	}{
+		{"page_totals", "page_url", pageTotals},
+		{"user_totals", "user_id", userTotals},
+	} {
+		if len(totals.counts) == 0 {
+			continue
+		}
+
+		// (page_url, event_type) or (user_id, event_type)
+		sorted := sortedKeys(totals.counts, func(a, b totalKey) int {
+			return cmp.Or(strings.Compare(a.Key, b.Key), strings.Compare(a.EventType, b.EventType))
+		})
+
+		keys := make([]string, 0, len(sorted))
+		types := make([]string, 0, len(sorted))
+		counts := make([]int64, 0, len(sorted))
+		for _, key := range sorted {
+			keys = append(keys, key.Key)
+			types = append(types, key.EventType)
+			counts = append(counts, totals.counts[key])
+		}
+
+		_, err := tx.ExecContext(ctx, fmt.Sprintf(`
+			INSERT INTO %[1]s (%[2]s, event_type, click_count)
+			SELECT * FROM unnest($1::text[], $2::text[], $3::bigint[])
+			ON CONFLICT (%[2]s, event_type) DO UPDATE SET click_count = %[1]s.click_count + EXCLUDED.click_count`,
+			totals.table, totals.column),
+			pq.Array(keys), pq.Array(types), pq.Array(counts))
+		if err != nil {
+			return fmt.Errorf("store %s: %w", totals.table, err)
+		}
+	}
+	return nil
+}
+
+// invalidateCache drops the cached counts of every pair, page and user the batch changed, for
+// all event types and for the event's own, in one round trip.
+func invalidateCache(ctx context.Context, events []event.ClickEvent) {
+	if len(events) == 0 {
+		return
+	}
+
+	seen := make(map[string]bool)
+	keys := make([]string, 0, len(events))
+	for _, e := range events {
+		for _, eventType := range []string{"", e.EventType} {
+			// pair, page total and user total, see GetEventCount
+			for _, key := range []string{
+				cache.CountKey(e.UserId, e.PageUrl, eventType),
+				cache.CountKey("", e.PageUrl, eventType),
+				cache.CountKey(e.UserId, "", eventType),
+			} {
+				if !seen[key] {
+					seen[key] = true
+					keys = append(keys, key)
+				}
+			}
+		}
+	}
+
+	KeyDeleted, err := rdb.Del(ctx, keys...).Result()
+
+	if err != nil {
+		log.Printf("error: Can't delete the existing cache: %v", err)
+	} else {
+		log.Printf("successfully deleted old cache: %d", KeyDeleted)
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"event-analytics/internal/event"
+	"event-analytics/internal/uniques"
+)
+
+// updateUniques PFADDs the user_id of every event into the hourly and daily HyperLogLogs of
+// its page and of the whole site. Adding the same user twice is a no-op, so replays are safe.
+// Events older than a key's retention are skipped for it, the key would already be gone.
+func updateUniques(ctx context.Context, events []event.ClickEvent) {
+	if len(events) == 0 {
+		return
+	}
+
+	now := time.Now()
+	users := make(map[string]map[string]bool)
+	expireAt := make(map[string]time.Time)
+
+	for _, e := range events {
+		for _, granularity := range []string{uniques.Hour, uniques.Day} {
+			width, retention := uniques.Width(granularity), uniques.Retention(granularity)
+			bucket := e.TimeStamp.UTC().Truncate(width)
+			expiry := bucket.Add(width + retention)
+			if !expiry.After(now) {
+				continue
+			}
+
+			for _, page := range []string{"", e.PageUrl} {
+				key := uniques.Key(granularity, page, bucket)
+				if users[key] == nil {
+					users[key] = make(map[string]bool)
+				}
+				users[key][e.UserId] = true
+				expireAt[key] = expiry
+			}
+		}
+	}
+	if len(users) == 0 {
+		return
+	}
+
+	pipe := rdb.Pipeline()
+	for key, members := range users {
+		args := make([]interface{}, 0, len(members))
+		for member := range members {
+			args = append(args, member)
+		}
+		pipe.PFAdd(ctx, key, args...)
+		pipe.ExpireAt(ctx, key, expireAt[key])
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("error: Can't update the unique user counts: %v", err)
+	}
+}
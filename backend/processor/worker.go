@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"event-analytics/internal/config"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/segmentio/kafka-go"
+)
+
+// number of workers processing partitions concurrently
+var concurrency = config.GetInt("PROCESSOR_CONCURRENCY", 4)
+
+var (
+	workerMessagesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "processor_worker_messages_total",
+		Help: "Messages stored and committed, by worker.",
+	}, []string{"worker"})
+	workerBatchesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "processor_worker_batches_total",
+		Help: "Batches stored and committed, by worker.",
+	}, []string{"worker"})
+	workerBatchSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "processor_worker_batch_duration_seconds",
+		Help:    "Time to store and commit a batch, retries included, by worker.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"worker"})
+	workerQueueDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "processor_worker_queue_depth",
+		Help: "Messages fetched but not yet batched, by worker.",
+	}, []string{"worker"})
+	workerLastOffset = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "processor_worker_committed_offset",
+		Help: "Last committed offset, by worker and partition.",
+	}, []string{"worker", "partition"})
+)
+
+func registerWorkerMetrics() {
+	prometheus.MustRegister(workerMessagesTotal, workerBatchesTotal, workerBatchSeconds, workerQueueDepth, workerLastOffset)
+}
+
+// worker batches, stores and commits the messages of the partitions assigned to it. Every
+// partition belongs to exactly one worker, so its messages are stored and its offsets are
+// committed in order: committing an offset in kafka.Reader commits everything before it in
+// that partition, which is only safe when nothing earlier is still being processed.
+type worker struct {
+	id       int
+	label    string
+	messages chan kafka.Message
+}
+
+func newWorkers(n int) []*worker {
+	workers := make([]*worker, n)
+	for i := range workers {
+		workers[i] = &worker{
+			id:       i,
+			label:    strconv.Itoa(i),
+			messages: make(chan kafka.Message, batchSize),
+		}
+	}
+	return workers
+}
+
+// runWorkers starts every worker and returns once all of them drained their channels. ctx
+// stops their retries, writeCtx bounds the writes still running after it.
+func runWorkers(ctx, writeCtx context.Context, workers []*worker) {
+	var wg sync.WaitGroup
+	for _, w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.run(ctx, writeCtx)
+		}()
+	}
+	wg.Wait()
+}
+
+// run consumes the worker's channel until it is closed, or until shutdown interrupted retries.
+func (w *worker) run(ctx, writeCtx context.Context) {
+	batch := make([]kafka.Message, 0, batchSize)
+	ticker := time.NewTicker(batchTimeout)
+	defer ticker.Stop()
+
+	// flush stores the batch and commits its offsets. The offsets are only committed once
+	// the events are safely in Postgres; it reports false when shutdown interrupted retries.
+	flush := func() bool {
+		if len(batch) == 0 {
+			return true
+		}
+		start := time.Now()
+
+		for attempt := 1; ; attempt++ {
+			err := processBatch(ctx, writeCtx, batch)
+			if err == nil {
+				break
+			}
+			if ctx.Err() != nil {
+				return false // uncommitted, redelivered after restart
+			}
+
+			// storing is idempotent, so the whole batch is simply processed again
+			delay := backoff(attempt)
+			log.Printf("Worker %d failed to process batch of %d, retrying in %s: %v", w.id, len(batch), delay, err)
+			select {
+			case <-ctx.Done():
+				return false
+			case <-time.After(delay):
+			}
+		}
+
+		if err := consumer.CommitMessages(writeCtx, batch...); err != nil {
+			log.Printf("Failed to commit batch of %d: %v", len(batch), err)
+		} else {
+			for _, msg := range batch {
+				workerLastOffset.WithLabelValues(w.label, strconv.Itoa(msg.Partition)).Set(float64(msg.Offset))
+			}
+		}
+
+		workerMessagesTotal.WithLabelValues(w.label).Add(float64(len(batch)))
+		workerBatchesTotal.WithLabelValues(w.label).Inc()
+		workerBatchSeconds.WithLabelValues(w.label).Observe(time.Since(start).Seconds())
+		log.Printf("Processed batch of %d messages (worker %d)", len(batch), w.id)
+		batch = batch[:0]
+		return true
+	}
+
+	for {
+		select {
+		case msg, ok := <-w.messages:
+			if !ok {
+				flush() // shutting down, store what was already fetched
+				return
+			}
+			workerQueueDepth.WithLabelValues(w.label).Set(float64(len(w.messages)))
+			batch = append(batch, msg)
+			if len(batch) >= batchSize && !flush() {
+				return
+			}
+		case <-ticker.C:
+			if !flush() {
+				return
+			}
+		}
+	}
+}
@@ -9,6 +9,8 @@ package proto
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -21,10 +23,163 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type Interval int32
+
+const (
+	Interval_INTERVAL_UNSPECIFIED Interval = 0 // hour
+	Interval_INTERVAL_MINUTE      Interval = 1
+	Interval_INTERVAL_HOUR        Interval = 2
+	Interval_INTERVAL_DAY         Interval = 3
+)
+
+// Enum value maps for Interval.
+var (
+	Interval_name = map[int32]string{
+		0: "INTERVAL_UNSPECIFIED",
+		1: "INTERVAL_MINUTE",
+		2: "INTERVAL_HOUR",
+		3: "INTERVAL_DAY",
+	}
+	Interval_value = map[string]int32{
+		"INTERVAL_UNSPECIFIED": 0,
+		"INTERVAL_MINUTE":      1,
+		"INTERVAL_HOUR":        2,
+		"INTERVAL_DAY":         3,
+	}
+)
+
+func (x Interval) Enum() *Interval {
+	p := new(Interval)
+	*p = x
+	return p
+}
+
+func (x Interval) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Interval) Descriptor() protoreflect.EnumDescriptor {
+	return file_analytics_proto_enumTypes[0].Descriptor()
+}
+
+func (Interval) Type() protoreflect.EnumType {
+	return &file_analytics_proto_enumTypes[0]
+}
+
+func (x Interval) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Interval.Descriptor instead.
+func (Interval) EnumDescriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{0}
+}
+
+type RetentionPeriod int32
+
+const (
+	RetentionPeriod_RETENTION_PERIOD_UNSPECIFIED RetentionPeriod = 0 // week
+	RetentionPeriod_RETENTION_PERIOD_DAY         RetentionPeriod = 1
+	RetentionPeriod_RETENTION_PERIOD_WEEK        RetentionPeriod = 2 // starting on Monday, UTC
+)
+
+// Enum value maps for RetentionPeriod.
+var (
+	RetentionPeriod_name = map[int32]string{
+		0: "RETENTION_PERIOD_UNSPECIFIED",
+		1: "RETENTION_PERIOD_DAY",
+		2: "RETENTION_PERIOD_WEEK",
+	}
+	RetentionPeriod_value = map[string]int32{
+		"RETENTION_PERIOD_UNSPECIFIED": 0,
+		"RETENTION_PERIOD_DAY":         1,
+		"RETENTION_PERIOD_WEEK":        2,
+	}
+)
+
+func (x RetentionPeriod) Enum() *RetentionPeriod {
+	p := new(RetentionPeriod)
+	*p = x
+	return p
+}
+
+func (x RetentionPeriod) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RetentionPeriod) Descriptor() protoreflect.EnumDescriptor {
+	return file_analytics_proto_enumTypes[1].Descriptor()
+}
+
+func (RetentionPeriod) Type() protoreflect.EnumType {
+	return &file_analytics_proto_enumTypes[1]
+}
+
+func (x RetentionPeriod) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RetentionPeriod.Descriptor instead.
+func (RetentionPeriod) EnumDescriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{1}
+}
+
+type PathDirection int32
+
+const (
+	PathDirection_PATH_DIRECTION_UNSPECIFIED PathDirection = 0 // forward
+	PathDirection_PATH_DIRECTION_FORWARD     PathDirection = 1 // the pages visited after page_url
+	PathDirection_PATH_DIRECTION_BACKWARD    PathDirection = 2 // the pages visited before page_url
+)
+
+// Enum value maps for PathDirection.
+var (
+	PathDirection_name = map[int32]string{
+		0: "PATH_DIRECTION_UNSPECIFIED",
+		1: "PATH_DIRECTION_FORWARD",
+		2: "PATH_DIRECTION_BACKWARD",
+	}
+	PathDirection_value = map[string]int32{
+		"PATH_DIRECTION_UNSPECIFIED": 0,
+		"PATH_DIRECTION_FORWARD":     1,
+		"PATH_DIRECTION_BACKWARD":    2,
+	}
+)
+
+func (x PathDirection) Enum() *PathDirection {
+	p := new(PathDirection)
+	*p = x
+	return p
+}
+
+func (x PathDirection) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PathDirection) Descriptor() protoreflect.EnumDescriptor {
+	return file_analytics_proto_enumTypes[2].Descriptor()
+}
+
+func (PathDirection) Type() protoreflect.EnumType {
+	return &file_analytics_proto_enumTypes[2]
+}
+
+func (x PathDirection) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PathDirection.Descriptor instead.
+func (PathDirection) EnumDescriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{2}
+}
+
+// Omit user_id for the total of a page across users, or page_url for the total of a user
+// across pages. At least one is required.
 type EventCountRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	PageUrl       string                 `protobuf:"bytes,2,opt,name=page_url,json=pageUrl,proto3" json:"page_url,omitempty"`
+	EventType     string                 `protobuf:"bytes,3,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"` // optional, every type when empty
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -73,11 +228,19 @@ func (x *EventCountRequest) GetPageUrl() string {
 	return ""
 }
 
+func (x *EventCountRequest) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
 type EventCountResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Count         int64                  `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
 	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	PageUrl       string                 `protobuf:"bytes,3,opt,name=page_url,json=pageUrl,proto3" json:"page_url,omitempty"`
+	EventType     string                 `protobuf:"bytes,4,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -133,20 +296,2451 @@ func (x *EventCountResponse) GetPageUrl() string {
 	return ""
 }
 
-var File_analytics_proto protoreflect.FileDescriptor
+func (x *EventCountResponse) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
 
-const file_analytics_proto_rawDesc = "" +
-	"\n" +
-	"\x0fanalytics.proto\x12\tanalytics\"G\n" +
-	"\x11EventCountRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x19\n" +
-	"\bpage_url\x18\x02 \x01(\tR\apageUrl\"^\n" +
-	"\x12EventCountResponse\x12\x14\n" +
-	"\x05count\x18\x01 \x01(\x03R\x05count\x12\x17\n" +
-	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x19\n" +
-	"\bpage_url\x18\x03 \x01(\tR\apageUrl2`\n" +
+// Counts events grouped by the value of one custom property.
+type PropertyCountRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PropertyKey   string                 `protobuf:"bytes,1,opt,name=property_key,json=propertyKey,proto3" json:"property_key,omitempty"`                                                // group by this property, events without it are skipped
+	Filters       map[string]string      `protobuf:"bytes,2,rep,name=filters,proto3" json:"filters,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // only count events whose properties contain all of these
+	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                                                               // optional
+	PageUrl       string                 `protobuf:"bytes,4,opt,name=page_url,json=pageUrl,proto3" json:"page_url,omitempty"`                                                            // optional
+	EventType     string                 `protobuf:"bytes,5,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`                                                      // optional
+	Limit         int32                  `protobuf:"varint,6,opt,name=limit,proto3" json:"limit,omitempty"`                                                                              // max groups returned, defaults to 50
+	Start         *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=start,proto3" json:"start,omitempty"`                                                                               // inclusive, defaults to 24h before end, at most 31 days before it
+	End           *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=end,proto3" json:"end,omitempty"`                                                                                   // exclusive, defaults to now
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PropertyCountRequest) Reset() {
+	*x = PropertyCountRequest{}
+	mi := &file_analytics_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PropertyCountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PropertyCountRequest) ProtoMessage() {}
+
+func (x *PropertyCountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PropertyCountRequest.ProtoReflect.Descriptor instead.
+func (*PropertyCountRequest) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PropertyCountRequest) GetPropertyKey() string {
+	if x != nil {
+		return x.PropertyKey
+	}
+	return ""
+}
+
+func (x *PropertyCountRequest) GetFilters() map[string]string {
+	if x != nil {
+		return x.Filters
+	}
+	return nil
+}
+
+func (x *PropertyCountRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *PropertyCountRequest) GetPageUrl() string {
+	if x != nil {
+		return x.PageUrl
+	}
+	return ""
+}
+
+func (x *PropertyCountRequest) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *PropertyCountRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *PropertyCountRequest) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *PropertyCountRequest) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+type PropertyCount struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Value         string                 `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PropertyCount) Reset() {
+	*x = PropertyCount{}
+	mi := &file_analytics_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PropertyCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PropertyCount) ProtoMessage() {}
+
+func (x *PropertyCount) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PropertyCount.ProtoReflect.Descriptor instead.
+func (*PropertyCount) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PropertyCount) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *PropertyCount) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type PropertyCountResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PropertyKey   string                 `protobuf:"bytes,1,opt,name=property_key,json=propertyKey,proto3" json:"property_key,omitempty"`
+	Counts        []*PropertyCount       `protobuf:"bytes,2,rep,name=counts,proto3" json:"counts,omitempty"`
+	Start         *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=start,proto3" json:"start,omitempty"`
+	End           *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=end,proto3" json:"end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PropertyCountResponse) Reset() {
+	*x = PropertyCountResponse{}
+	mi := &file_analytics_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PropertyCountResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PropertyCountResponse) ProtoMessage() {}
+
+func (x *PropertyCountResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PropertyCountResponse.ProtoReflect.Descriptor instead.
+func (*PropertyCountResponse) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PropertyCountResponse) GetPropertyKey() string {
+	if x != nil {
+		return x.PropertyKey
+	}
+	return ""
+}
+
+func (x *PropertyCountResponse) GetCounts() []*PropertyCount {
+	if x != nil {
+		return x.Counts
+	}
+	return nil
+}
+
+func (x *PropertyCountResponse) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *PropertyCountResponse) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+// Click counts per time bucket, read from the clicks_per_minute/hour/day rollups.
+type TimeSeriesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PageUrl       string                 `protobuf:"bytes,1,opt,name=page_url,json=pageUrl,proto3" json:"page_url,omitempty"`       // optional
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`          // optional
+	EventType     string                 `protobuf:"bytes,3,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"` // optional, every type when empty
+	Start         *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=start,proto3" json:"start,omitempty"`                          // inclusive, defaults to 24h before end
+	End           *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=end,proto3" json:"end,omitempty"`                              // exclusive, defaults to now
+	Interval      Interval               `protobuf:"varint,6,opt,name=interval,proto3,enum=analytics.Interval" json:"interval,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TimeSeriesRequest) Reset() {
+	*x = TimeSeriesRequest{}
+	mi := &file_analytics_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TimeSeriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimeSeriesRequest) ProtoMessage() {}
+
+func (x *TimeSeriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimeSeriesRequest.ProtoReflect.Descriptor instead.
+func (*TimeSeriesRequest) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *TimeSeriesRequest) GetPageUrl() string {
+	if x != nil {
+		return x.PageUrl
+	}
+	return ""
+}
+
+func (x *TimeSeriesRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *TimeSeriesRequest) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *TimeSeriesRequest) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *TimeSeriesRequest) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+func (x *TimeSeriesRequest) GetInterval() Interval {
+	if x != nil {
+		return x.Interval
+	}
+	return Interval_INTERVAL_UNSPECIFIED
+}
+
+type TimeSeriesPoint struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Bucket        *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=bucket,proto3" json:"bucket,omitempty"` // start of the bucket, UTC
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TimeSeriesPoint) Reset() {
+	*x = TimeSeriesPoint{}
+	mi := &file_analytics_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TimeSeriesPoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimeSeriesPoint) ProtoMessage() {}
+
+func (x *TimeSeriesPoint) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimeSeriesPoint.ProtoReflect.Descriptor instead.
+func (*TimeSeriesPoint) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *TimeSeriesPoint) GetBucket() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Bucket
+	}
+	return nil
+}
+
+func (x *TimeSeriesPoint) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type TimeSeriesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Interval      Interval               `protobuf:"varint,1,opt,name=interval,proto3,enum=analytics.Interval" json:"interval,omitempty"`
+	Start         *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=start,proto3" json:"start,omitempty"` // first bucket
+	End           *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=end,proto3" json:"end,omitempty"`
+	Total         int64                  `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"`
+	Points        []*TimeSeriesPoint     `protobuf:"bytes,5,rep,name=points,proto3" json:"points,omitempty"` // every bucket in [start, end), zero when empty
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TimeSeriesResponse) Reset() {
+	*x = TimeSeriesResponse{}
+	mi := &file_analytics_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TimeSeriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimeSeriesResponse) ProtoMessage() {}
+
+func (x *TimeSeriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimeSeriesResponse.ProtoReflect.Descriptor instead.
+func (*TimeSeriesResponse) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *TimeSeriesResponse) GetInterval() Interval {
+	if x != nil {
+		return x.Interval
+	}
+	return Interval_INTERVAL_UNSPECIFIED
+}
+
+func (x *TimeSeriesResponse) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *TimeSeriesResponse) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+func (x *TimeSeriesResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *TimeSeriesResponse) GetPoints() []*TimeSeriesPoint {
+	if x != nil {
+		return x.Points
+	}
+	return nil
+}
+
+// The most clicked pages or most active users over the last window.
+type TopRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Window        *durationpb.Duration   `protobuf:"bytes,1,opt,name=window,proto3" json:"window,omitempty"`                        // defaults to 1h
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`                         // defaults to 10, at most 100
+	EventType     string                 `protobuf:"bytes,3,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"` // optional, every type when empty
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TopRequest) Reset() {
+	*x = TopRequest{}
+	mi := &file_analytics_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TopRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TopRequest) ProtoMessage() {}
+
+func (x *TopRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TopRequest.ProtoReflect.Descriptor instead.
+func (*TopRequest) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *TopRequest) GetWindow() *durationpb.Duration {
+	if x != nil {
+		return x.Window
+	}
+	return nil
+}
+
+func (x *TopRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *TopRequest) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+type TopEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"` // page_url for TopPages, user_id for TopUsers
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TopEntry) Reset() {
+	*x = TopEntry{}
+	mi := &file_analytics_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TopEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TopEntry) ProtoMessage() {}
+
+func (x *TopEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TopEntry.ProtoReflect.Descriptor instead.
+func (*TopEntry) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *TopEntry) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *TopEntry) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type TopResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*TopEntry            `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"` // most clicks first
+	Since         *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=since,proto3" json:"since,omitempty"`     // start of the first bucket counted
+	Source        string                 `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`   // "redis" within the real-time horizon, "postgres" beyond it
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TopResponse) Reset() {
+	*x = TopResponse{}
+	mi := &file_analytics_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TopResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TopResponse) ProtoMessage() {}
+
+func (x *TopResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TopResponse.ProtoReflect.Descriptor instead.
+func (*TopResponse) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *TopResponse) GetEntries() []*TopEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+func (x *TopResponse) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+func (x *TopResponse) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+// Approximate distinct user_ids over a range, from HyperLogLogs.
+type UniqueUsersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PageUrl       string                 `protobuf:"bytes,1,opt,name=page_url,json=pageUrl,proto3" json:"page_url,omitempty"` // optional, the whole site when empty
+	Start         *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=start,proto3" json:"start,omitempty"`                    // inclusive, defaults to 24h before end
+	End           *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=end,proto3" json:"end,omitempty"`                        // exclusive, defaults to now
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UniqueUsersRequest) Reset() {
+	*x = UniqueUsersRequest{}
+	mi := &file_analytics_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UniqueUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UniqueUsersRequest) ProtoMessage() {}
+
+func (x *UniqueUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UniqueUsersRequest.ProtoReflect.Descriptor instead.
+func (*UniqueUsersRequest) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *UniqueUsersRequest) GetPageUrl() string {
+	if x != nil {
+		return x.PageUrl
+	}
+	return ""
+}
+
+func (x *UniqueUsersRequest) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *UniqueUsersRequest) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+type UniqueUsersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Count         int64                  `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	StandardError float64                `protobuf:"fixed64,2,opt,name=standard_error,json=standardError,proto3" json:"standard_error,omitempty"` // relative, 0.0081
+	LowerBound    int64                  `protobuf:"varint,3,opt,name=lower_bound,json=lowerBound,proto3" json:"lower_bound,omitempty"`           // count minus two standard errors, about 95% confidence
+	UpperBound    int64                  `protobuf:"varint,4,opt,name=upper_bound,json=upperBound,proto3" json:"upper_bound,omitempty"`           // count plus two standard errors
+	Start         *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=start,proto3" json:"start,omitempty"`                                        // the range actually counted, rounded out to whole hours
+	End           *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=end,proto3" json:"end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UniqueUsersResponse) Reset() {
+	*x = UniqueUsersResponse{}
+	mi := &file_analytics_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UniqueUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UniqueUsersResponse) ProtoMessage() {}
+
+func (x *UniqueUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UniqueUsersResponse.ProtoReflect.Descriptor instead.
+func (*UniqueUsersResponse) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *UniqueUsersResponse) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *UniqueUsersResponse) GetStandardError() float64 {
+	if x != nil {
+		return x.StandardError
+	}
+	return 0
+}
+
+func (x *UniqueUsersResponse) GetLowerBound() int64 {
+	if x != nil {
+		return x.LowerBound
+	}
+	return 0
+}
+
+func (x *UniqueUsersResponse) GetUpperBound() int64 {
+	if x != nil {
+		return x.UpperBound
+	}
+	return 0
+}
+
+func (x *UniqueUsersResponse) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *UniqueUsersResponse) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+// A user's sessions, newest first.
+type SessionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // required
+	Start         *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=start,proto3" json:"start,omitempty"`                 // optional, sessions started at or after
+	End           *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=end,proto3" json:"end,omitempty"`                     // optional, sessions started before
+	Limit         int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`                // defaults to 50, at most 1000
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionsRequest) Reset() {
+	*x = SessionsRequest{}
+	mi := &file_analytics_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionsRequest) ProtoMessage() {}
+
+func (x *SessionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionsRequest.ProtoReflect.Descriptor instead.
+func (*SessionsRequest) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SessionsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SessionsRequest) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *SessionsRequest) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+func (x *SessionsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type Session struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	StartedAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	EndedAt       *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=ended_at,json=endedAt,proto3" json:"ended_at,omitempty"` // unset while the session is open
+	Duration      *durationpb.Duration   `protobuf:"bytes,5,opt,name=duration,proto3" json:"duration,omitempty"`              // first to last event
+	EventCount    int32                  `protobuf:"varint,6,opt,name=event_count,json=eventCount,proto3" json:"event_count,omitempty"`
+	PageCount     int32                  `protobuf:"varint,7,opt,name=page_count,json=pageCount,proto3" json:"page_count,omitempty"` // distinct pages
+	EntryPage     string                 `protobuf:"bytes,8,opt,name=entry_page,json=entryPage,proto3" json:"entry_page,omitempty"`
+	ExitPage      string                 `protobuf:"bytes,9,opt,name=exit_page,json=exitPage,proto3" json:"exit_page,omitempty"`
+	Active        bool                   `protobuf:"varint,10,opt,name=active,proto3" json:"active,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Session) Reset() {
+	*x = Session{}
+	mi := &file_analytics_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Session) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Session) ProtoMessage() {}
+
+func (x *Session) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Session.ProtoReflect.Descriptor instead.
+func (*Session) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Session) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *Session) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Session) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *Session) GetEndedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndedAt
+	}
+	return nil
+}
+
+func (x *Session) GetDuration() *durationpb.Duration {
+	if x != nil {
+		return x.Duration
+	}
+	return nil
+}
+
+func (x *Session) GetEventCount() int32 {
+	if x != nil {
+		return x.EventCount
+	}
+	return 0
+}
+
+func (x *Session) GetPageCount() int32 {
+	if x != nil {
+		return x.PageCount
+	}
+	return 0
+}
+
+func (x *Session) GetEntryPage() string {
+	if x != nil {
+		return x.EntryPage
+	}
+	return ""
+}
+
+func (x *Session) GetExitPage() string {
+	if x != nil {
+		return x.ExitPage
+	}
+	return ""
+}
+
+func (x *Session) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+type SessionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sessions      []*Session             `protobuf:"bytes,1,rep,name=sessions,proto3" json:"sessions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionsResponse) Reset() {
+	*x = SessionsResponse{}
+	mi := &file_analytics_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionsResponse) ProtoMessage() {}
+
+func (x *SessionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionsResponse.ProtoReflect.Descriptor instead.
+func (*SessionsResponse) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SessionsResponse) GetSessions() []*Session {
+	if x != nil {
+		return x.Sessions
+	}
+	return nil
+}
+
+// Aggregate statistics of the sessions started in a range.
+type SessionStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // optional, every user when empty
+	Start         *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=start,proto3" json:"start,omitempty"`                 // defaults to 24h before end
+	End           *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=end,proto3" json:"end,omitempty"`                     // defaults to now
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionStatsRequest) Reset() {
+	*x = SessionStatsRequest{}
+	mi := &file_analytics_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionStatsRequest) ProtoMessage() {}
+
+func (x *SessionStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionStatsRequest.ProtoReflect.Descriptor instead.
+func (*SessionStatsRequest) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *SessionStatsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SessionStatsRequest) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *SessionStatsRequest) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+type SessionStatsResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SessionCount   int64                  `protobuf:"varint,1,opt,name=session_count,json=sessionCount,proto3" json:"session_count,omitempty"`
+	ActiveCount    int64                  `protobuf:"varint,2,opt,name=active_count,json=activeCount,proto3" json:"active_count,omitempty"`         // still open
+	AvgDuration    *durationpb.Duration   `protobuf:"bytes,3,opt,name=avg_duration,json=avgDuration,proto3" json:"avg_duration,omitempty"`          // of ended sessions
+	MedianDuration *durationpb.Duration   `protobuf:"bytes,4,opt,name=median_duration,json=medianDuration,proto3" json:"median_duration,omitempty"` // of ended sessions
+	AvgEvents      float64                `protobuf:"fixed64,5,opt,name=avg_events,json=avgEvents,proto3" json:"avg_events,omitempty"`
+	AvgPages       float64                `protobuf:"fixed64,6,opt,name=avg_pages,json=avgPages,proto3" json:"avg_pages,omitempty"`
+	BounceRate     float64                `protobuf:"fixed64,7,opt,name=bounce_rate,json=bounceRate,proto3" json:"bounce_rate,omitempty"` // share of ended sessions with a single event
+	Start          *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=start,proto3" json:"start,omitempty"`
+	End            *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=end,proto3" json:"end,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SessionStatsResponse) Reset() {
+	*x = SessionStatsResponse{}
+	mi := &file_analytics_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionStatsResponse) ProtoMessage() {}
+
+func (x *SessionStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionStatsResponse.ProtoReflect.Descriptor instead.
+func (*SessionStatsResponse) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *SessionStatsResponse) GetSessionCount() int64 {
+	if x != nil {
+		return x.SessionCount
+	}
+	return 0
+}
+
+func (x *SessionStatsResponse) GetActiveCount() int64 {
+	if x != nil {
+		return x.ActiveCount
+	}
+	return 0
+}
+
+func (x *SessionStatsResponse) GetAvgDuration() *durationpb.Duration {
+	if x != nil {
+		return x.AvgDuration
+	}
+	return nil
+}
+
+func (x *SessionStatsResponse) GetMedianDuration() *durationpb.Duration {
+	if x != nil {
+		return x.MedianDuration
+	}
+	return nil
+}
+
+func (x *SessionStatsResponse) GetAvgEvents() float64 {
+	if x != nil {
+		return x.AvgEvents
+	}
+	return 0
+}
+
+func (x *SessionStatsResponse) GetAvgPages() float64 {
+	if x != nil {
+		return x.AvgPages
+	}
+	return 0
+}
+
+func (x *SessionStatsResponse) GetBounceRate() float64 {
+	if x != nil {
+		return x.BounceRate
+	}
+	return 0
+}
+
+func (x *SessionStatsResponse) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *SessionStatsResponse) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+// A funnel step matches events by page, type or both; at least one must be set.
+type FunnelStep struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PageUrl       string                 `protobuf:"bytes,1,opt,name=page_url,json=pageUrl,proto3" json:"page_url,omitempty"` // * matches any run of characters, e.g. /blog/*
+	EventType     string                 `protobuf:"bytes,2,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FunnelStep) Reset() {
+	*x = FunnelStep{}
+	mi := &file_analytics_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FunnelStep) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FunnelStep) ProtoMessage() {}
+
+func (x *FunnelStep) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FunnelStep.ProtoReflect.Descriptor instead.
+func (*FunnelStep) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *FunnelStep) GetPageUrl() string {
+	if x != nil {
+		return x.PageUrl
+	}
+	return ""
+}
+
+func (x *FunnelStep) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+type FunnelRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Steps         []*FunnelStep          `protobuf:"bytes,1,rep,name=steps,proto3" json:"steps,omitempty"`   // in order, 2 to 10 of them
+	Window        *durationpb.Duration   `protobuf:"bytes,2,opt,name=window,proto3" json:"window,omitempty"` // from the first step to the last, defaults to 24h
+	Start         *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=start,proto3" json:"start,omitempty"`   // users entering the funnel from, defaults to 24h before end
+	End           *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=end,proto3" json:"end,omitempty"`       // exclusive, defaults to now
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FunnelRequest) Reset() {
+	*x = FunnelRequest{}
+	mi := &file_analytics_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FunnelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FunnelRequest) ProtoMessage() {}
+
+func (x *FunnelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FunnelRequest.ProtoReflect.Descriptor instead.
+func (*FunnelRequest) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *FunnelRequest) GetSteps() []*FunnelStep {
+	if x != nil {
+		return x.Steps
+	}
+	return nil
+}
+
+func (x *FunnelRequest) GetWindow() *durationpb.Duration {
+	if x != nil {
+		return x.Window
+	}
+	return nil
+}
+
+func (x *FunnelRequest) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *FunnelRequest) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+type FunnelStepResult struct {
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	Step                   *FunnelStep            `protobuf:"bytes,1,opt,name=step,proto3" json:"step,omitempty"`
+	Users                  int64                  `protobuf:"varint,2,opt,name=users,proto3" json:"users,omitempty"`
+	ConversionPercent      float64                `protobuf:"fixed64,3,opt,name=conversion_percent,json=conversionPercent,proto3" json:"conversion_percent,omitempty"`                  // of the users who entered the funnel
+	StepConversionPercent  float64                `protobuf:"fixed64,4,opt,name=step_conversion_percent,json=stepConversionPercent,proto3" json:"step_conversion_percent,omitempty"`    // of the users who reached the previous step
+	DropOffPercent         float64                `protobuf:"fixed64,5,opt,name=drop_off_percent,json=dropOffPercent,proto3" json:"drop_off_percent,omitempty"`                         // of the users who reached the previous step
+	MedianTimeFromPrevious *durationpb.Duration   `protobuf:"bytes,6,opt,name=median_time_from_previous,json=medianTimeFromPrevious,proto3" json:"median_time_from_previous,omitempty"` // unset for the first step
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *FunnelStepResult) Reset() {
+	*x = FunnelStepResult{}
+	mi := &file_analytics_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FunnelStepResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FunnelStepResult) ProtoMessage() {}
+
+func (x *FunnelStepResult) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FunnelStepResult.ProtoReflect.Descriptor instead.
+func (*FunnelStepResult) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *FunnelStepResult) GetStep() *FunnelStep {
+	if x != nil {
+		return x.Step
+	}
+	return nil
+}
+
+func (x *FunnelStepResult) GetUsers() int64 {
+	if x != nil {
+		return x.Users
+	}
+	return 0
+}
+
+func (x *FunnelStepResult) GetConversionPercent() float64 {
+	if x != nil {
+		return x.ConversionPercent
+	}
+	return 0
+}
+
+func (x *FunnelStepResult) GetStepConversionPercent() float64 {
+	if x != nil {
+		return x.StepConversionPercent
+	}
+	return 0
+}
+
+func (x *FunnelStepResult) GetDropOffPercent() float64 {
+	if x != nil {
+		return x.DropOffPercent
+	}
+	return 0
+}
+
+func (x *FunnelStepResult) GetMedianTimeFromPrevious() *durationpb.Duration {
+	if x != nil {
+		return x.MedianTimeFromPrevious
+	}
+	return nil
+}
+
+type FunnelResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Steps         []*FunnelStepResult    `protobuf:"bytes,1,rep,name=steps,proto3" json:"steps,omitempty"`
+	Window        *durationpb.Duration   `protobuf:"bytes,2,opt,name=window,proto3" json:"window,omitempty"`
+	Start         *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=start,proto3" json:"start,omitempty"`
+	End           *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=end,proto3" json:"end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FunnelResponse) Reset() {
+	*x = FunnelResponse{}
+	mi := &file_analytics_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FunnelResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FunnelResponse) ProtoMessage() {}
+
+func (x *FunnelResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FunnelResponse.ProtoReflect.Descriptor instead.
+func (*FunnelResponse) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *FunnelResponse) GetSteps() []*FunnelStepResult {
+	if x != nil {
+		return x.Steps
+	}
+	return nil
+}
+
+func (x *FunnelResponse) GetWindow() *durationpb.Duration {
+	if x != nil {
+		return x.Window
+	}
+	return nil
+}
+
+func (x *FunnelResponse) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *FunnelResponse) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+type RetentionRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Period          RetentionPeriod        `protobuf:"varint,1,opt,name=period,proto3,enum=analytics.RetentionPeriod" json:"period,omitempty"`
+	CohortEventType string                 `protobuf:"bytes,2,opt,name=cohort_event_type,json=cohortEventType,proto3" json:"cohort_event_type,omitempty"` // optional, users are grouped by their first event of this type, any type when empty
+	ReturnEventType string                 `protobuf:"bytes,3,opt,name=return_event_type,json=returnEventType,proto3" json:"return_event_type,omitempty"` // optional, a return is an event of this type, any type when empty
+	Periods         int32                  `protobuf:"varint,4,opt,name=periods,proto3" json:"periods,omitempty"`                                         // later periods to follow each cohort for, defaults to 8, at most 90
+	Start           *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=start,proto3" json:"start,omitempty"`                                              // first cohort, defaults to periods before end
+	End             *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=end,proto3" json:"end,omitempty"`                                                  // exclusive, defaults to now
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *RetentionRequest) Reset() {
+	*x = RetentionRequest{}
+	mi := &file_analytics_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetentionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetentionRequest) ProtoMessage() {}
+
+func (x *RetentionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetentionRequest.ProtoReflect.Descriptor instead.
+func (*RetentionRequest) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *RetentionRequest) GetPeriod() RetentionPeriod {
+	if x != nil {
+		return x.Period
+	}
+	return RetentionPeriod_RETENTION_PERIOD_UNSPECIFIED
+}
+
+func (x *RetentionRequest) GetCohortEventType() string {
+	if x != nil {
+		return x.CohortEventType
+	}
+	return ""
+}
+
+func (x *RetentionRequest) GetReturnEventType() string {
+	if x != nil {
+		return x.ReturnEventType
+	}
+	return ""
+}
+
+func (x *RetentionRequest) GetPeriods() int32 {
+	if x != nil {
+		return x.Periods
+	}
+	return 0
+}
+
+func (x *RetentionRequest) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *RetentionRequest) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+type RetentionCohort struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	CohortStart *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=cohort_start,json=cohortStart,proto3" json:"cohort_start,omitempty"`
+	Users       int64                  `protobuf:"varint,2,opt,name=users,proto3" json:"users,omitempty"`
+	// indexed by periods since the cohort, index 0 being the cohort's own period; only periods
+	// that have started are included
+	Returned      []int64   `protobuf:"varint,3,rep,packed,name=returned,proto3" json:"returned,omitempty"`
+	Percent       []float64 `protobuf:"fixed64,4,rep,packed,name=percent,proto3" json:"percent,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RetentionCohort) Reset() {
+	*x = RetentionCohort{}
+	mi := &file_analytics_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetentionCohort) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetentionCohort) ProtoMessage() {}
+
+func (x *RetentionCohort) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetentionCohort.ProtoReflect.Descriptor instead.
+func (*RetentionCohort) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *RetentionCohort) GetCohortStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CohortStart
+	}
+	return nil
+}
+
+func (x *RetentionCohort) GetUsers() int64 {
+	if x != nil {
+		return x.Users
+	}
+	return 0
+}
+
+func (x *RetentionCohort) GetReturned() []int64 {
+	if x != nil {
+		return x.Returned
+	}
+	return nil
+}
+
+func (x *RetentionCohort) GetPercent() []float64 {
+	if x != nil {
+		return x.Percent
+	}
+	return nil
+}
+
+type RetentionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Period        RetentionPeriod        `protobuf:"varint,1,opt,name=period,proto3,enum=analytics.RetentionPeriod" json:"period,omitempty"`
+	Periods       int32                  `protobuf:"varint,2,opt,name=periods,proto3" json:"periods,omitempty"`
+	Cohorts       []*RetentionCohort     `protobuf:"bytes,3,rep,name=cohorts,proto3" json:"cohorts,omitempty"` // oldest first
+	Start         *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=start,proto3" json:"start,omitempty"`
+	End           *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=end,proto3" json:"end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RetentionResponse) Reset() {
+	*x = RetentionResponse{}
+	mi := &file_analytics_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetentionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetentionResponse) ProtoMessage() {}
+
+func (x *RetentionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetentionResponse.ProtoReflect.Descriptor instead.
+func (*RetentionResponse) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *RetentionResponse) GetPeriod() RetentionPeriod {
+	if x != nil {
+		return x.Period
+	}
+	return RetentionPeriod_RETENTION_PERIOD_UNSPECIFIED
+}
+
+func (x *RetentionResponse) GetPeriods() int32 {
+	if x != nil {
+		return x.Periods
+	}
+	return 0
+}
+
+func (x *RetentionResponse) GetCohorts() []*RetentionCohort {
+	if x != nil {
+		return x.Cohorts
+	}
+	return nil
+}
+
+func (x *RetentionResponse) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *RetentionResponse) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+type PathsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PageUrl       string                 `protobuf:"bytes,1,opt,name=page_url,json=pageUrl,proto3" json:"page_url,omitempty"` // required
+	Direction     PathDirection          `protobuf:"varint,2,opt,name=direction,proto3,enum=analytics.PathDirection" json:"direction,omitempty"`
+	Steps         int32                  `protobuf:"varint,3,opt,name=steps,proto3" json:"steps,omitempty"`                          // pages to follow away from page_url, defaults to 3, at most 5
+	MinWeight     int64                  `protobuf:"varint,4,opt,name=min_weight,json=minWeight,proto3" json:"min_weight,omitempty"` // edges followed fewer times are dropped, defaults to 1
+	Start         *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=start,proto3" json:"start,omitempty"`                           // sessions started from, defaults to 24h before end
+	End           *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=end,proto3" json:"end,omitempty"`                               // exclusive, defaults to now
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PathsRequest) Reset() {
+	*x = PathsRequest{}
+	mi := &file_analytics_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PathsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PathsRequest) ProtoMessage() {}
+
+func (x *PathsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PathsRequest.ProtoReflect.Descriptor instead.
+func (*PathsRequest) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *PathsRequest) GetPageUrl() string {
+	if x != nil {
+		return x.PageUrl
+	}
+	return ""
+}
+
+func (x *PathsRequest) GetDirection() PathDirection {
+	if x != nil {
+		return x.Direction
+	}
+	return PathDirection_PATH_DIRECTION_UNSPECIFIED
+}
+
+func (x *PathsRequest) GetSteps() int32 {
+	if x != nil {
+		return x.Steps
+	}
+	return 0
+}
+
+func (x *PathsRequest) GetMinWeight() int64 {
+	if x != nil {
+		return x.MinWeight
+	}
+	return 0
+}
+
+func (x *PathsRequest) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *PathsRequest) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+// A node is a page at a step from page_url, negative steps come before it.
+type PathNode struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // "<step>:<page_url>"
+	PageUrl       string                 `protobuf:"bytes,2,opt,name=page_url,json=pageUrl,proto3" json:"page_url,omitempty"`
+	Step          int32                  `protobuf:"varint,3,opt,name=step,proto3" json:"step,omitempty"`
+	Weight        int64                  `protobuf:"varint,4,opt,name=weight,proto3" json:"weight,omitempty"` // visits of page_url whose path goes through the node
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PathNode) Reset() {
+	*x = PathNode{}
+	mi := &file_analytics_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PathNode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PathNode) ProtoMessage() {}
+
+func (x *PathNode) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PathNode.ProtoReflect.Descriptor instead.
+func (*PathNode) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *PathNode) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PathNode) GetPageUrl() string {
+	if x != nil {
+		return x.PageUrl
+	}
+	return ""
+}
+
+func (x *PathNode) GetStep() int32 {
+	if x != nil {
+		return x.Step
+	}
+	return 0
+}
+
+func (x *PathNode) GetWeight() int64 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+type PathEdge struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Source        string                 `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"` // node id, the earlier page
+	Target        string                 `protobuf:"bytes,2,opt,name=target,proto3" json:"target,omitempty"` // node id, the later page
+	Weight        int64                  `protobuf:"varint,3,opt,name=weight,proto3" json:"weight,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PathEdge) Reset() {
+	*x = PathEdge{}
+	mi := &file_analytics_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PathEdge) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PathEdge) ProtoMessage() {}
+
+func (x *PathEdge) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PathEdge.ProtoReflect.Descriptor instead.
+func (*PathEdge) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *PathEdge) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *PathEdge) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *PathEdge) GetWeight() int64 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+type Path struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pages         []string               `protobuf:"bytes,1,rep,name=pages,proto3" json:"pages,omitempty"` // in visit order, including page_url
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Path) Reset() {
+	*x = Path{}
+	mi := &file_analytics_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Path) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Path) ProtoMessage() {}
+
+func (x *Path) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Path.ProtoReflect.Descriptor instead.
+func (*Path) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *Path) GetPages() []string {
+	if x != nil {
+		return x.Pages
+	}
+	return nil
+}
+
+func (x *Path) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type PathsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Total         int64                  `protobuf:"varint,1,opt,name=total,proto3" json:"total,omitempty"` // visits of page_url
+	Nodes         []*PathNode            `protobuf:"bytes,2,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	Edges         []*PathEdge            `protobuf:"bytes,3,rep,name=edges,proto3" json:"edges,omitempty"` // heaviest first
+	Paths         []*Path                `protobuf:"bytes,4,rep,name=paths,proto3" json:"paths,omitempty"` // most common first
+	Direction     PathDirection          `protobuf:"varint,5,opt,name=direction,proto3,enum=analytics.PathDirection" json:"direction,omitempty"`
+	Steps         int32                  `protobuf:"varint,6,opt,name=steps,proto3" json:"steps,omitempty"`
+	Start         *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=start,proto3" json:"start,omitempty"`
+	End           *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=end,proto3" json:"end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PathsResponse) Reset() {
+	*x = PathsResponse{}
+	mi := &file_analytics_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PathsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PathsResponse) ProtoMessage() {}
+
+func (x *PathsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PathsResponse.ProtoReflect.Descriptor instead.
+func (*PathsResponse) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *PathsResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *PathsResponse) GetNodes() []*PathNode {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+func (x *PathsResponse) GetEdges() []*PathEdge {
+	if x != nil {
+		return x.Edges
+	}
+	return nil
+}
+
+func (x *PathsResponse) GetPaths() []*Path {
+	if x != nil {
+		return x.Paths
+	}
+	return nil
+}
+
+func (x *PathsResponse) GetDirection() PathDirection {
+	if x != nil {
+		return x.Direction
+	}
+	return PathDirection_PATH_DIRECTION_UNSPECIFIED
+}
+
+func (x *PathsResponse) GetSteps() int32 {
+	if x != nil {
+		return x.Steps
+	}
+	return 0
+}
+
+func (x *PathsResponse) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *PathsResponse) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+// Filters for SubscribeCounts, empty ones match anything.
+type SubscribeCountsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	PageUrl       string                 `protobuf:"bytes,2,opt,name=page_url,json=pageUrl,proto3" json:"page_url,omitempty"`
+	EventType     string                 `protobuf:"bytes,3,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeCountsRequest) Reset() {
+	*x = SubscribeCountsRequest{}
+	mi := &file_analytics_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeCountsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeCountsRequest) ProtoMessage() {}
+
+func (x *SubscribeCountsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeCountsRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeCountsRequest) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *SubscribeCountsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SubscribeCountsRequest) GetPageUrl() string {
+	if x != nil {
+		return x.PageUrl
+	}
+	return ""
+}
+
+func (x *SubscribeCountsRequest) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+type CountChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	PageUrl       string                 `protobuf:"bytes,2,opt,name=page_url,json=pageUrl,proto3" json:"page_url,omitempty"`
+	EventType     string                 `protobuf:"bytes,3,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	Delta         int64                  `protobuf:"varint,4,opt,name=delta,proto3" json:"delta,omitempty"` // clicks added
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CountChange) Reset() {
+	*x = CountChange{}
+	mi := &file_analytics_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CountChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountChange) ProtoMessage() {}
+
+func (x *CountChange) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountChange.ProtoReflect.Descriptor instead.
+func (*CountChange) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *CountChange) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CountChange) GetPageUrl() string {
+	if x != nil {
+		return x.PageUrl
+	}
+	return ""
+}
+
+func (x *CountChange) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *CountChange) GetDelta() int64 {
+	if x != nil {
+		return x.Delta
+	}
+	return 0
+}
+
+// One processed batch, only sent when it changed a count matching the filters.
+type CountUpdate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Changes       []*CountChange         `protobuf:"bytes,1,rep,name=changes,proto3" json:"changes,omitempty"`
+	Delta         int64                  `protobuf:"varint,2,opt,name=delta,proto3" json:"delta,omitempty"` // sum of the changes
+	PublishedAt   *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=published_at,json=publishedAt,proto3" json:"published_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CountUpdate) Reset() {
+	*x = CountUpdate{}
+	mi := &file_analytics_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CountUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountUpdate) ProtoMessage() {}
+
+func (x *CountUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountUpdate.ProtoReflect.Descriptor instead.
+func (*CountUpdate) Descriptor() ([]byte, []int) {
+	return file_analytics_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *CountUpdate) GetChanges() []*CountChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+func (x *CountUpdate) GetDelta() int64 {
+	if x != nil {
+		return x.Delta
+	}
+	return 0
+}
+
+func (x *CountUpdate) GetPublishedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PublishedAt
+	}
+	return nil
+}
+
+var File_analytics_proto protoreflect.FileDescriptor
+
+const file_analytics_proto_rawDesc = "" +
+	"\n" +
+	"\x0fanalytics.proto\x12\tanalytics\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"f\n" +
+	"\x11EventCountRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x19\n" +
+	"\bpage_url\x18\x02 \x01(\tR\apageUrl\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x03 \x01(\tR\teventType\"}\n" +
+	"\x12EventCountResponse\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\x03R\x05count\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x19\n" +
+	"\bpage_url\x18\x03 \x01(\tR\apageUrl\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x04 \x01(\tR\teventType\"\x86\x03\n" +
+	"\x14PropertyCountRequest\x12!\n" +
+	"\fproperty_key\x18\x01 \x01(\tR\vpropertyKey\x12F\n" +
+	"\afilters\x18\x02 \x03(\v2,.analytics.PropertyCountRequest.FiltersEntryR\afilters\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x19\n" +
+	"\bpage_url\x18\x04 \x01(\tR\apageUrl\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x05 \x01(\tR\teventType\x12\x14\n" +
+	"\x05limit\x18\x06 \x01(\x05R\x05limit\x120\n" +
+	"\x05start\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12,\n" +
+	"\x03end\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\x03end\x1a:\n" +
+	"\fFiltersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\";\n" +
+	"\rPropertyCount\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\tR\x05value\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\"\xcc\x01\n" +
+	"\x15PropertyCountResponse\x12!\n" +
+	"\fproperty_key\x18\x01 \x01(\tR\vpropertyKey\x120\n" +
+	"\x06counts\x18\x02 \x03(\v2\x18.analytics.PropertyCountR\x06counts\x120\n" +
+	"\x05start\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12,\n" +
+	"\x03end\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x03end\"\xf7\x01\n" +
+	"\x11TimeSeriesRequest\x12\x19\n" +
+	"\bpage_url\x18\x01 \x01(\tR\apageUrl\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x03 \x01(\tR\teventType\x120\n" +
+	"\x05start\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12,\n" +
+	"\x03end\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x03end\x12/\n" +
+	"\binterval\x18\x06 \x01(\x0e2\x13.analytics.IntervalR\binterval\"[\n" +
+	"\x0fTimeSeriesPoint\x122\n" +
+	"\x06bucket\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x06bucket\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\"\xef\x01\n" +
+	"\x12TimeSeriesResponse\x12/\n" +
+	"\binterval\x18\x01 \x01(\x0e2\x13.analytics.IntervalR\binterval\x120\n" +
+	"\x05start\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12,\n" +
+	"\x03end\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x03end\x12\x14\n" +
+	"\x05total\x18\x04 \x01(\x03R\x05total\x122\n" +
+	"\x06points\x18\x05 \x03(\v2\x1a.analytics.TimeSeriesPointR\x06points\"t\n" +
+	"\n" +
+	"TopRequest\x121\n" +
+	"\x06window\x18\x01 \x01(\v2\x19.google.protobuf.DurationR\x06window\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x03 \x01(\tR\teventType\"2\n" +
+	"\bTopEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\"\x86\x01\n" +
+	"\vTopResponse\x12-\n" +
+	"\aentries\x18\x01 \x03(\v2\x13.analytics.TopEntryR\aentries\x120\n" +
+	"\x05since\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x05since\x12\x16\n" +
+	"\x06source\x18\x03 \x01(\tR\x06source\"\x8f\x01\n" +
+	"\x12UniqueUsersRequest\x12\x19\n" +
+	"\bpage_url\x18\x01 \x01(\tR\apageUrl\x120\n" +
+	"\x05start\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12,\n" +
+	"\x03end\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x03end\"\xf4\x01\n" +
+	"\x13UniqueUsersResponse\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\x03R\x05count\x12%\n" +
+	"\x0estandard_error\x18\x02 \x01(\x01R\rstandardError\x12\x1f\n" +
+	"\vlower_bound\x18\x03 \x01(\x03R\n" +
+	"lowerBound\x12\x1f\n" +
+	"\vupper_bound\x18\x04 \x01(\x03R\n" +
+	"upperBound\x120\n" +
+	"\x05start\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12,\n" +
+	"\x03end\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x03end\"\xa0\x01\n" +
+	"\x0fSessionsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x120\n" +
+	"\x05start\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12,\n" +
+	"\x03end\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x03end\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\"\xfe\x02\n" +
+	"\aSession\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x129\n" +
+	"\n" +
+	"started_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x125\n" +
+	"\bended_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\aendedAt\x125\n" +
+	"\bduration\x18\x05 \x01(\v2\x19.google.protobuf.DurationR\bduration\x12\x1f\n" +
+	"\vevent_count\x18\x06 \x01(\x05R\n" +
+	"eventCount\x12\x1d\n" +
+	"\n" +
+	"page_count\x18\a \x01(\x05R\tpageCount\x12\x1d\n" +
+	"\n" +
+	"entry_page\x18\b \x01(\tR\tentryPage\x12\x1b\n" +
+	"\texit_page\x18\t \x01(\tR\bexitPage\x12\x16\n" +
+	"\x06active\x18\n" +
+	" \x01(\bR\x06active\"B\n" +
+	"\x10SessionsResponse\x12.\n" +
+	"\bsessions\x18\x01 \x03(\v2\x12.analytics.SessionR\bsessions\"\x8e\x01\n" +
+	"\x13SessionStatsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x120\n" +
+	"\x05start\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12,\n" +
+	"\x03end\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x03end\"\x9d\x03\n" +
+	"\x14SessionStatsResponse\x12#\n" +
+	"\rsession_count\x18\x01 \x01(\x03R\fsessionCount\x12!\n" +
+	"\factive_count\x18\x02 \x01(\x03R\vactiveCount\x12<\n" +
+	"\favg_duration\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\vavgDuration\x12B\n" +
+	"\x0fmedian_duration\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\x0emedianDuration\x12\x1d\n" +
+	"\n" +
+	"avg_events\x18\x05 \x01(\x01R\tavgEvents\x12\x1b\n" +
+	"\tavg_pages\x18\x06 \x01(\x01R\bavgPages\x12\x1f\n" +
+	"\vbounce_rate\x18\a \x01(\x01R\n" +
+	"bounceRate\x120\n" +
+	"\x05start\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12,\n" +
+	"\x03end\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\x03end\"F\n" +
+	"\n" +
+	"FunnelStep\x12\x19\n" +
+	"\bpage_url\x18\x01 \x01(\tR\apageUrl\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x02 \x01(\tR\teventType\"\xcf\x01\n" +
+	"\rFunnelRequest\x12+\n" +
+	"\x05steps\x18\x01 \x03(\v2\x15.analytics.FunnelStepR\x05steps\x121\n" +
+	"\x06window\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\x06window\x120\n" +
+	"\x05start\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12,\n" +
+	"\x03end\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x03end\"\xba\x02\n" +
+	"\x10FunnelStepResult\x12)\n" +
+	"\x04step\x18\x01 \x01(\v2\x15.analytics.FunnelStepR\x04step\x12\x14\n" +
+	"\x05users\x18\x02 \x01(\x03R\x05users\x12-\n" +
+	"\x12conversion_percent\x18\x03 \x01(\x01R\x11conversionPercent\x126\n" +
+	"\x17step_conversion_percent\x18\x04 \x01(\x01R\x15stepConversionPercent\x12(\n" +
+	"\x10drop_off_percent\x18\x05 \x01(\x01R\x0edropOffPercent\x12T\n" +
+	"\x19median_time_from_previous\x18\x06 \x01(\v2\x19.google.protobuf.DurationR\x16medianTimeFromPrevious\"\xd6\x01\n" +
+	"\x0eFunnelResponse\x121\n" +
+	"\x05steps\x18\x01 \x03(\v2\x1b.analytics.FunnelStepResultR\x05steps\x121\n" +
+	"\x06window\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\x06window\x120\n" +
+	"\x05start\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12,\n" +
+	"\x03end\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x03end\"\x98\x02\n" +
+	"\x10RetentionRequest\x122\n" +
+	"\x06period\x18\x01 \x01(\x0e2\x1a.analytics.RetentionPeriodR\x06period\x12*\n" +
+	"\x11cohort_event_type\x18\x02 \x01(\tR\x0fcohortEventType\x12*\n" +
+	"\x11return_event_type\x18\x03 \x01(\tR\x0freturnEventType\x12\x18\n" +
+	"\aperiods\x18\x04 \x01(\x05R\aperiods\x120\n" +
+	"\x05start\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12,\n" +
+	"\x03end\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x03end\"\x9c\x01\n" +
+	"\x0fRetentionCohort\x12=\n" +
+	"\fcohort_start\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\vcohortStart\x12\x14\n" +
+	"\x05users\x18\x02 \x01(\x03R\x05users\x12\x1a\n" +
+	"\breturned\x18\x03 \x03(\x03R\breturned\x12\x18\n" +
+	"\apercent\x18\x04 \x03(\x01R\apercent\"\xf7\x01\n" +
+	"\x11RetentionResponse\x122\n" +
+	"\x06period\x18\x01 \x01(\x0e2\x1a.analytics.RetentionPeriodR\x06period\x12\x18\n" +
+	"\aperiods\x18\x02 \x01(\x05R\aperiods\x124\n" +
+	"\acohorts\x18\x03 \x03(\v2\x1a.analytics.RetentionCohortR\acohorts\x120\n" +
+	"\x05start\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12,\n" +
+	"\x03end\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x03end\"\xf6\x01\n" +
+	"\fPathsRequest\x12\x19\n" +
+	"\bpage_url\x18\x01 \x01(\tR\apageUrl\x126\n" +
+	"\tdirection\x18\x02 \x01(\x0e2\x18.analytics.PathDirectionR\tdirection\x12\x14\n" +
+	"\x05steps\x18\x03 \x01(\x05R\x05steps\x12\x1d\n" +
+	"\n" +
+	"min_weight\x18\x04 \x01(\x03R\tminWeight\x120\n" +
+	"\x05start\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12,\n" +
+	"\x03end\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x03end\"a\n" +
+	"\bPathNode\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
+	"\bpage_url\x18\x02 \x01(\tR\apageUrl\x12\x12\n" +
+	"\x04step\x18\x03 \x01(\x05R\x04step\x12\x16\n" +
+	"\x06weight\x18\x04 \x01(\x03R\x06weight\"R\n" +
+	"\bPathEdge\x12\x16\n" +
+	"\x06source\x18\x01 \x01(\tR\x06source\x12\x16\n" +
+	"\x06target\x18\x02 \x01(\tR\x06target\x12\x16\n" +
+	"\x06weight\x18\x03 \x01(\x03R\x06weight\"2\n" +
+	"\x04Path\x12\x14\n" +
+	"\x05pages\x18\x01 \x03(\tR\x05pages\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\"\xd0\x02\n" +
+	"\rPathsResponse\x12\x14\n" +
+	"\x05total\x18\x01 \x01(\x03R\x05total\x12)\n" +
+	"\x05nodes\x18\x02 \x03(\v2\x13.analytics.PathNodeR\x05nodes\x12)\n" +
+	"\x05edges\x18\x03 \x03(\v2\x13.analytics.PathEdgeR\x05edges\x12%\n" +
+	"\x05paths\x18\x04 \x03(\v2\x0f.analytics.PathR\x05paths\x126\n" +
+	"\tdirection\x18\x05 \x01(\x0e2\x18.analytics.PathDirectionR\tdirection\x12\x14\n" +
+	"\x05steps\x18\x06 \x01(\x05R\x05steps\x120\n" +
+	"\x05start\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12,\n" +
+	"\x03end\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\x03end\"k\n" +
+	"\x16SubscribeCountsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x19\n" +
+	"\bpage_url\x18\x02 \x01(\tR\apageUrl\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x03 \x01(\tR\teventType\"v\n" +
+	"\vCountChange\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x19\n" +
+	"\bpage_url\x18\x02 \x01(\tR\apageUrl\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x03 \x01(\tR\teventType\x12\x14\n" +
+	"\x05delta\x18\x04 \x01(\x03R\x05delta\"\x94\x01\n" +
+	"\vCountUpdate\x120\n" +
+	"\achanges\x18\x01 \x03(\v2\x16.analytics.CountChangeR\achanges\x12\x14\n" +
+	"\x05delta\x18\x02 \x01(\x03R\x05delta\x12=\n" +
+	"\fpublished_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\vpublishedAt*^\n" +
+	"\bInterval\x12\x18\n" +
+	"\x14INTERVAL_UNSPECIFIED\x10\x00\x12\x13\n" +
+	"\x0fINTERVAL_MINUTE\x10\x01\x12\x11\n" +
+	"\rINTERVAL_HOUR\x10\x02\x12\x10\n" +
+	"\fINTERVAL_DAY\x10\x03*h\n" +
+	"\x0fRetentionPeriod\x12 \n" +
+	"\x1cRETENTION_PERIOD_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14RETENTION_PERIOD_DAY\x10\x01\x12\x19\n" +
+	"\x15RETENTION_PERIOD_WEEK\x10\x02*h\n" +
+	"\rPathDirection\x12\x1e\n" +
+	"\x1aPATH_DIRECTION_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16PATH_DIRECTION_FORWARD\x10\x01\x12\x1b\n" +
+	"\x17PATH_DIRECTION_BACKWARD\x10\x022\x85\a\n" +
 	"\x10AnalyticsService\x12L\n" +
-	"\rGetEventCount\x12\x1c.analytics.EventCountRequest\x1a\x1d.analytics.EventCountResponseB\x17Z\x15event-analytics/protob\x06proto3"
+	"\rGetEventCount\x12\x1c.analytics.EventCountRequest\x1a\x1d.analytics.EventCountResponse\x12V\n" +
+	"\x11GetPropertyCounts\x12\x1f.analytics.PropertyCountRequest\x1a .analytics.PropertyCountResponse\x12L\n" +
+	"\rGetTimeSeries\x12\x1c.analytics.TimeSeriesRequest\x1a\x1d.analytics.TimeSeriesResponse\x129\n" +
+	"\bTopPages\x12\x15.analytics.TopRequest\x1a\x16.analytics.TopResponse\x129\n" +
+	"\bTopUsers\x12\x15.analytics.TopRequest\x1a\x16.analytics.TopResponse\x12O\n" +
+	"\x0eGetUniqueUsers\x12\x1d.analytics.UniqueUsersRequest\x1a\x1e.analytics.UniqueUsersResponse\x12F\n" +
+	"\vGetSessions\x12\x1a.analytics.SessionsRequest\x1a\x1b.analytics.SessionsResponse\x12R\n" +
+	"\x0fGetSessionStats\x12\x1e.analytics.SessionStatsRequest\x1a\x1f.analytics.SessionStatsResponse\x12@\n" +
+	"\tGetFunnel\x12\x18.analytics.FunnelRequest\x1a\x19.analytics.FunnelResponse\x12I\n" +
+	"\fGetRetention\x12\x1b.analytics.RetentionRequest\x1a\x1c.analytics.RetentionResponse\x12=\n" +
+	"\bGetPaths\x12\x17.analytics.PathsRequest\x1a\x18.analytics.PathsResponse\x12N\n" +
+	"\x0fSubscribeCounts\x12!.analytics.SubscribeCountsRequest\x1a\x16.analytics.CountUpdate0\x01B\x17Z\x15event-analytics/protob\x06proto3"
 
 var (
 	file_analytics_proto_rawDescOnce sync.Once
@@ -160,19 +2754,141 @@ func file_analytics_proto_rawDescGZIP() []byte {
 	return file_analytics_proto_rawDescData
 }
 
-var file_analytics_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_analytics_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_analytics_proto_msgTypes = make([]protoimpl.MessageInfo, 34)
 var file_analytics_proto_goTypes = []any{
-	(*EventCountRequest)(nil),  // 0: analytics.EventCountRequest
-	(*EventCountResponse)(nil), // 1: analytics.EventCountResponse
+	(Interval)(0),                  // 0: analytics.Interval
+	(RetentionPeriod)(0),           // 1: analytics.RetentionPeriod
+	(PathDirection)(0),             // 2: analytics.PathDirection
+	(*EventCountRequest)(nil),      // 3: analytics.EventCountRequest
+	(*EventCountResponse)(nil),     // 4: analytics.EventCountResponse
+	(*PropertyCountRequest)(nil),   // 5: analytics.PropertyCountRequest
+	(*PropertyCount)(nil),          // 6: analytics.PropertyCount
+	(*PropertyCountResponse)(nil),  // 7: analytics.PropertyCountResponse
+	(*TimeSeriesRequest)(nil),      // 8: analytics.TimeSeriesRequest
+	(*TimeSeriesPoint)(nil),        // 9: analytics.TimeSeriesPoint
+	(*TimeSeriesResponse)(nil),     // 10: analytics.TimeSeriesResponse
+	(*TopRequest)(nil),             // 11: analytics.TopRequest
+	(*TopEntry)(nil),               // 12: analytics.TopEntry
+	(*TopResponse)(nil),            // 13: analytics.TopResponse
+	(*UniqueUsersRequest)(nil),     // 14: analytics.UniqueUsersRequest
+	(*UniqueUsersResponse)(nil),    // 15: analytics.UniqueUsersResponse
+	(*SessionsRequest)(nil),        // 16: analytics.SessionsRequest
+	(*Session)(nil),                // 17: analytics.Session
+	(*SessionsResponse)(nil),       // 18: analytics.SessionsResponse
+	(*SessionStatsRequest)(nil),    // 19: analytics.SessionStatsRequest
+	(*SessionStatsResponse)(nil),   // 20: analytics.SessionStatsResponse
+	(*FunnelStep)(nil),             // 21: analytics.FunnelStep
+	(*FunnelRequest)(nil),          // 22: analytics.FunnelRequest
+	(*FunnelStepResult)(nil),       // 23: analytics.FunnelStepResult
+	(*FunnelResponse)(nil),         // 24: analytics.FunnelResponse
+	(*RetentionRequest)(nil),       // 25: analytics.RetentionRequest
+	(*RetentionCohort)(nil),        // 26: analytics.RetentionCohort
+	(*RetentionResponse)(nil),      // 27: analytics.RetentionResponse
+	(*PathsRequest)(nil),           // 28: analytics.PathsRequest
+	(*PathNode)(nil),               // 29: analytics.PathNode
+	(*PathEdge)(nil),               // 30: analytics.PathEdge
+	(*Path)(nil),                   // 31: analytics.Path
+	(*PathsResponse)(nil),          // 32: analytics.PathsResponse
+	(*SubscribeCountsRequest)(nil), // 33: analytics.SubscribeCountsRequest
+	(*CountChange)(nil),            // 34: analytics.CountChange
+	(*CountUpdate)(nil),            // 35: analytics.CountUpdate
+	nil,                            // 36: analytics.PropertyCountRequest.FiltersEntry
+	(*timestamppb.Timestamp)(nil),  // 37: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),    // 38: google.protobuf.Duration
 }
 var file_analytics_proto_depIdxs = []int32{
-	0, // 0: analytics.AnalyticsService.GetEventCount:input_type -> analytics.EventCountRequest
-	1, // 1: analytics.AnalyticsService.GetEventCount:output_type -> analytics.EventCountResponse
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	36, // 0: analytics.PropertyCountRequest.filters:type_name -> analytics.PropertyCountRequest.FiltersEntry
+	37, // 1: analytics.PropertyCountRequest.start:type_name -> google.protobuf.Timestamp
+	37, // 2: analytics.PropertyCountRequest.end:type_name -> google.protobuf.Timestamp
+	6,  // 3: analytics.PropertyCountResponse.counts:type_name -> analytics.PropertyCount
+	37, // 4: analytics.PropertyCountResponse.start:type_name -> google.protobuf.Timestamp
+	37, // 5: analytics.PropertyCountResponse.end:type_name -> google.protobuf.Timestamp
+	37, // 6: analytics.TimeSeriesRequest.start:type_name -> google.protobuf.Timestamp
+	37, // 7: analytics.TimeSeriesRequest.end:type_name -> google.protobuf.Timestamp
+	0,  // 8: analytics.TimeSeriesRequest.interval:type_name -> analytics.Interval
+	37, // 9: analytics.TimeSeriesPoint.bucket:type_name -> google.protobuf.Timestamp
+	0,  // 10: analytics.TimeSeriesResponse.interval:type_name -> analytics.Interval
+	37, // 11: analytics.TimeSeriesResponse.start:type_name -> google.protobuf.Timestamp
+	37, // 12: analytics.TimeSeriesResponse.end:type_name -> google.protobuf.Timestamp
+	9,  // 13: analytics.TimeSeriesResponse.points:type_name -> analytics.TimeSeriesPoint
+	38, // 14: analytics.TopRequest.window:type_name -> google.protobuf.Duration
+	12, // 15: analytics.TopResponse.entries:type_name -> analytics.TopEntry
+	37, // 16: analytics.TopResponse.since:type_name -> google.protobuf.Timestamp
+	37, // 17: analytics.UniqueUsersRequest.start:type_name -> google.protobuf.Timestamp
+	37, // 18: analytics.UniqueUsersRequest.end:type_name -> google.protobuf.Timestamp
+	37, // 19: analytics.UniqueUsersResponse.start:type_name -> google.protobuf.Timestamp
+	37, // 20: analytics.UniqueUsersResponse.end:type_name -> google.protobuf.Timestamp
+	37, // 21: analytics.SessionsRequest.start:type_name -> google.protobuf.Timestamp
+	37, // 22: analytics.SessionsRequest.end:type_name -> google.protobuf.Timestamp
+	37, // 23: analytics.Session.started_at:type_name -> google.protobuf.Timestamp
+	37, // 24: analytics.Session.ended_at:type_name -> google.protobuf.Timestamp
+	38, // 25: analytics.Session.duration:type_name -> google.protobuf.Duration
+	17, // 26: analytics.SessionsResponse.sessions:type_name -> analytics.Session
+	37, // 27: analytics.SessionStatsRequest.start:type_name -> google.protobuf.Timestamp
+	37, // 28: analytics.SessionStatsRequest.end:type_name -> google.protobuf.Timestamp
+	38, // 29: analytics.SessionStatsResponse.avg_duration:type_name -> google.protobuf.Duration
+	38, // 30: analytics.SessionStatsResponse.median_duration:type_name -> google.protobuf.Duration
+	37, // 31: analytics.SessionStatsResponse.start:type_name -> google.protobuf.Timestamp
+	37, // 32: analytics.SessionStatsResponse.end:type_name -> google.protobuf.Timestamp
+	21, // 33: analytics.FunnelRequest.steps:type_name -> analytics.FunnelStep
+	38, // 34: analytics.FunnelRequest.window:type_name -> google.protobuf.Duration
+	37, // 35: analytics.FunnelRequest.start:type_name -> google.protobuf.Timestamp
+	37, // 36: analytics.FunnelRequest.end:type_name -> google.protobuf.Timestamp
+	21, // 37: analytics.FunnelStepResult.step:type_name -> analytics.FunnelStep
+	38, // 38: analytics.FunnelStepResult.median_time_from_previous:type_name -> google.protobuf.Duration
+	23, // 39: analytics.FunnelResponse.steps:type_name -> analytics.FunnelStepResult
+	38, // 40: analytics.FunnelResponse.window:type_name -> google.protobuf.Duration
+	37, // 41: analytics.FunnelResponse.start:type_name -> google.protobuf.Timestamp
+	37, // 42: analytics.FunnelResponse.end:type_name -> google.protobuf.Timestamp
+	1,  // 43: analytics.RetentionRequest.period:type_name -> analytics.RetentionPeriod
+	37, // 44: analytics.RetentionRequest.start:type_name -> google.protobuf.Timestamp
+	37, // 45: analytics.RetentionRequest.end:type_name -> google.protobuf.Timestamp
+	37, // 46: analytics.RetentionCohort.cohort_start:type_name -> google.protobuf.Timestamp
+	1,  // 47: analytics.RetentionResponse.period:type_name -> analytics.RetentionPeriod
+	26, // 48: analytics.RetentionResponse.cohorts:type_name -> analytics.RetentionCohort
+	37, // 49: analytics.RetentionResponse.start:type_name -> google.protobuf.Timestamp
+	37, // 50: analytics.RetentionResponse.end:type_name -> google.protobuf.Timestamp
+	2,  // 51: analytics.PathsRequest.direction:type_name -> analytics.PathDirection
+	37, // 52: analytics.PathsRequest.start:type_name -> google.protobuf.Timestamp
+	37, // 53: analytics.PathsRequest.end:type_name -> google.protobuf.Timestamp
+	29, // 54: analytics.PathsResponse.nodes:type_name -> analytics.PathNode
+	30, // 55: analytics.PathsResponse.edges:type_name -> analytics.PathEdge
+	31, // 56: analytics.PathsResponse.paths:type_name -> analytics.Path
+	2,  // 57: analytics.PathsResponse.direction:type_name -> analytics.PathDirection
+	37, // 58: analytics.PathsResponse.start:type_name -> google.protobuf.Timestamp
+	37, // 59: analytics.PathsResponse.end:type_name -> google.protobuf.Timestamp
+	34, // 60: analytics.CountUpdate.changes:type_name -> analytics.CountChange
+	37, // 61: analytics.CountUpdate.published_at:type_name -> google.protobuf.Timestamp
+	3,  // 62: analytics.AnalyticsService.GetEventCount:input_type -> analytics.EventCountRequest
+	5,  // 63: analytics.AnalyticsService.GetPropertyCounts:input_type -> analytics.PropertyCountRequest
+	8,  // 64: analytics.AnalyticsService.GetTimeSeries:input_type -> analytics.TimeSeriesRequest
+	11, // 65: analytics.AnalyticsService.TopPages:input_type -> analytics.TopRequest
+	11, // 66: analytics.AnalyticsService.TopUsers:input_type -> analytics.TopRequest
+	14, // 67: analytics.AnalyticsService.GetUniqueUsers:input_type -> analytics.UniqueUsersRequest
+	16, // 68: analytics.AnalyticsService.GetSessions:input_type -> analytics.SessionsRequest
+	19, // 69: analytics.AnalyticsService.GetSessionStats:input_type -> analytics.SessionStatsRequest
+	22, // 70: analytics.AnalyticsService.GetFunnel:input_type -> analytics.FunnelRequest
+	25, // 71: analytics.AnalyticsService.GetRetention:input_type -> analytics.RetentionRequest
+	28, // 72: analytics.AnalyticsService.GetPaths:input_type -> analytics.PathsRequest
+	33, // 73: analytics.AnalyticsService.SubscribeCounts:input_type -> analytics.SubscribeCountsRequest
+	4,  // 74: analytics.AnalyticsService.GetEventCount:output_type -> analytics.EventCountResponse
+	7,  // 75: analytics.AnalyticsService.GetPropertyCounts:output_type -> analytics.PropertyCountResponse
+	10, // 76: analytics.AnalyticsService.GetTimeSeries:output_type -> analytics.TimeSeriesResponse
+	13, // 77: analytics.AnalyticsService.TopPages:output_type -> analytics.TopResponse
+	13, // 78: analytics.AnalyticsService.TopUsers:output_type -> analytics.TopResponse
+	15, // 79: analytics.AnalyticsService.GetUniqueUsers:output_type -> analytics.UniqueUsersResponse
+	18, // 80: analytics.AnalyticsService.GetSessions:output_type -> analytics.SessionsResponse
+	20, // 81: analytics.AnalyticsService.GetSessionStats:output_type -> analytics.SessionStatsResponse
+	24, // 82: analytics.AnalyticsService.GetFunnel:output_type -> analytics.FunnelResponse
+	27, // 83: analytics.AnalyticsService.GetRetention:output_type -> analytics.RetentionResponse
+	32, // 84: analytics.AnalyticsService.GetPaths:output_type -> analytics.PathsResponse
+	35, // 85: analytics.AnalyticsService.SubscribeCounts:output_type -> analytics.CountUpdate
+	74, // [74:86] is the sub-list for method output_type
+	62, // [62:74] is the sub-list for method input_type
+	62, // [62:62] is the sub-list for extension type_name
+	62, // [62:62] is the sub-list for extension extendee
+	0,  // [0:62] is the sub-list for field type_name
 }
 
 func init() { file_analytics_proto_init() }
@@ -185,13 +2901,14 @@ func file_analytics_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_analytics_proto_rawDesc), len(file_analytics_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   2,
+			NumEnums:      3,
+			NumMessages:   34,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_analytics_proto_goTypes,
 		DependencyIndexes: file_analytics_proto_depIdxs,
+		EnumInfos:         file_analytics_proto_enumTypes,
 		MessageInfos:      file_analytics_proto_msgTypes,
 	}.Build()
 	File_analytics_proto = out.File
@@ -19,7 +19,18 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	AnalyticsService_GetEventCount_FullMethodName = "/analytics.AnalyticsService/GetEventCount"
+	AnalyticsService_GetEventCount_FullMethodName     = "/analytics.AnalyticsService/GetEventCount"
+	AnalyticsService_GetPropertyCounts_FullMethodName = "/analytics.AnalyticsService/GetPropertyCounts"
+	AnalyticsService_GetTimeSeries_FullMethodName     = "/analytics.AnalyticsService/GetTimeSeries"
+	AnalyticsService_TopPages_FullMethodName          = "/analytics.AnalyticsService/TopPages"
+	AnalyticsService_TopUsers_FullMethodName          = "/analytics.AnalyticsService/TopUsers"
+	AnalyticsService_GetUniqueUsers_FullMethodName    = "/analytics.AnalyticsService/GetUniqueUsers"
+	AnalyticsService_GetSessions_FullMethodName       = "/analytics.AnalyticsService/GetSessions"
+	AnalyticsService_GetSessionStats_FullMethodName   = "/analytics.AnalyticsService/GetSessionStats"
+	AnalyticsService_GetFunnel_FullMethodName         = "/analytics.AnalyticsService/GetFunnel"
+	AnalyticsService_GetRetention_FullMethodName      = "/analytics.AnalyticsService/GetRetention"
+	AnalyticsService_GetPaths_FullMethodName          = "/analytics.AnalyticsService/GetPaths"
+	AnalyticsService_SubscribeCounts_FullMethodName   = "/analytics.AnalyticsService/SubscribeCounts"
 )
 
 // AnalyticsServiceClient is the client API for AnalyticsService service.
@@ -27,6 +38,17 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type AnalyticsServiceClient interface {
 	GetEventCount(ctx context.Context, in *EventCountRequest, opts ...grpc.CallOption) (*EventCountResponse, error)
+	GetPropertyCounts(ctx context.Context, in *PropertyCountRequest, opts ...grpc.CallOption) (*PropertyCountResponse, error)
+	GetTimeSeries(ctx context.Context, in *TimeSeriesRequest, opts ...grpc.CallOption) (*TimeSeriesResponse, error)
+	TopPages(ctx context.Context, in *TopRequest, opts ...grpc.CallOption) (*TopResponse, error)
+	TopUsers(ctx context.Context, in *TopRequest, opts ...grpc.CallOption) (*TopResponse, error)
+	GetUniqueUsers(ctx context.Context, in *UniqueUsersRequest, opts ...grpc.CallOption) (*UniqueUsersResponse, error)
+	GetSessions(ctx context.Context, in *SessionsRequest, opts ...grpc.CallOption) (*SessionsResponse, error)
+	GetSessionStats(ctx context.Context, in *SessionStatsRequest, opts ...grpc.CallOption) (*SessionStatsResponse, error)
+	GetFunnel(ctx context.Context, in *FunnelRequest, opts ...grpc.CallOption) (*FunnelResponse, error)
+	GetRetention(ctx context.Context, in *RetentionRequest, opts ...grpc.CallOption) (*RetentionResponse, error)
+	GetPaths(ctx context.Context, in *PathsRequest, opts ...grpc.CallOption) (*PathsResponse, error)
+	SubscribeCounts(ctx context.Context, in *SubscribeCountsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CountUpdate], error)
 }
 
 type analyticsServiceClient struct {
@@ -47,11 +69,141 @@ func (c *analyticsServiceClient) GetEventCount(ctx context.Context, in *EventCou
 	return out, nil
 }
 
+func (c *analyticsServiceClient) GetPropertyCounts(ctx context.Context, in *PropertyCountRequest, opts ...grpc.CallOption) (*PropertyCountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PropertyCountResponse)
+	err := c.cc.Invoke(ctx, AnalyticsService_GetPropertyCounts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *analyticsServiceClient) GetTimeSeries(ctx context.Context, in *TimeSeriesRequest, opts ...grpc.CallOption) (*TimeSeriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TimeSeriesResponse)
+	err := c.cc.Invoke(ctx, AnalyticsService_GetTimeSeries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *analyticsServiceClient) TopPages(ctx context.Context, in *TopRequest, opts ...grpc.CallOption) (*TopResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TopResponse)
+	err := c.cc.Invoke(ctx, AnalyticsService_TopPages_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *analyticsServiceClient) TopUsers(ctx context.Context, in *TopRequest, opts ...grpc.CallOption) (*TopResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TopResponse)
+	err := c.cc.Invoke(ctx, AnalyticsService_TopUsers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *analyticsServiceClient) GetUniqueUsers(ctx context.Context, in *UniqueUsersRequest, opts ...grpc.CallOption) (*UniqueUsersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UniqueUsersResponse)
+	err := c.cc.Invoke(ctx, AnalyticsService_GetUniqueUsers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *analyticsServiceClient) GetSessions(ctx context.Context, in *SessionsRequest, opts ...grpc.CallOption) (*SessionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SessionsResponse)
+	err := c.cc.Invoke(ctx, AnalyticsService_GetSessions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *analyticsServiceClient) GetSessionStats(ctx context.Context, in *SessionStatsRequest, opts ...grpc.CallOption) (*SessionStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SessionStatsResponse)
+	err := c.cc.Invoke(ctx, AnalyticsService_GetSessionStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *analyticsServiceClient) GetFunnel(ctx context.Context, in *FunnelRequest, opts ...grpc.CallOption) (*FunnelResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FunnelResponse)
+	err := c.cc.Invoke(ctx, AnalyticsService_GetFunnel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *analyticsServiceClient) GetRetention(ctx context.Context, in *RetentionRequest, opts ...grpc.CallOption) (*RetentionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RetentionResponse)
+	err := c.cc.Invoke(ctx, AnalyticsService_GetRetention_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *analyticsServiceClient) GetPaths(ctx context.Context, in *PathsRequest, opts ...grpc.CallOption) (*PathsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PathsResponse)
+	err := c.cc.Invoke(ctx, AnalyticsService_GetPaths_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *analyticsServiceClient) SubscribeCounts(ctx context.Context, in *SubscribeCountsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CountUpdate], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AnalyticsService_ServiceDesc.Streams[0], AnalyticsService_SubscribeCounts_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeCountsRequest, CountUpdate]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AnalyticsService_SubscribeCountsClient = grpc.ServerStreamingClient[CountUpdate]
+
 // AnalyticsServiceServer is the server API for AnalyticsService service.
 // All implementations must embed UnimplementedAnalyticsServiceServer
 // for forward compatibility.
 type AnalyticsServiceServer interface {
 	GetEventCount(context.Context, *EventCountRequest) (*EventCountResponse, error)
+	GetPropertyCounts(context.Context, *PropertyCountRequest) (*PropertyCountResponse, error)
+	GetTimeSeries(context.Context, *TimeSeriesRequest) (*TimeSeriesResponse, error)
+	TopPages(context.Context, *TopRequest) (*TopResponse, error)
+	TopUsers(context.Context, *TopRequest) (*TopResponse, error)
+	GetUniqueUsers(context.Context, *UniqueUsersRequest) (*UniqueUsersResponse, error)
+	GetSessions(context.Context, *SessionsRequest) (*SessionsResponse, error)
+	GetSessionStats(context.Context, *SessionStatsRequest) (*SessionStatsResponse, error)
+	GetFunnel(context.Context, *FunnelRequest) (*FunnelResponse, error)
+	GetRetention(context.Context, *RetentionRequest) (*RetentionResponse, error)
+	GetPaths(context.Context, *PathsRequest) (*PathsResponse, error)
+	SubscribeCounts(*SubscribeCountsRequest, grpc.ServerStreamingServer[CountUpdate]) error
 	mustEmbedUnimplementedAnalyticsServiceServer()
 }
 
@@ -65,6 +217,39 @@ type UnimplementedAnalyticsServiceServer struct{}
 func (UnimplementedAnalyticsServiceServer) GetEventCount(context.Context, *EventCountRequest) (*EventCountResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetEventCount not implemented")
 }
+func (UnimplementedAnalyticsServiceServer) GetPropertyCounts(context.Context, *PropertyCountRequest) (*PropertyCountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPropertyCounts not implemented")
+}
+func (UnimplementedAnalyticsServiceServer) GetTimeSeries(context.Context, *TimeSeriesRequest) (*TimeSeriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTimeSeries not implemented")
+}
+func (UnimplementedAnalyticsServiceServer) TopPages(context.Context, *TopRequest) (*TopResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TopPages not implemented")
+}
+func (UnimplementedAnalyticsServiceServer) TopUsers(context.Context, *TopRequest) (*TopResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TopUsers not implemented")
+}
+func (UnimplementedAnalyticsServiceServer) GetUniqueUsers(context.Context, *UniqueUsersRequest) (*UniqueUsersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUniqueUsers not implemented")
+}
+func (UnimplementedAnalyticsServiceServer) GetSessions(context.Context, *SessionsRequest) (*SessionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSessions not implemented")
+}
+func (UnimplementedAnalyticsServiceServer) GetSessionStats(context.Context, *SessionStatsRequest) (*SessionStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSessionStats not implemented")
+}
+func (UnimplementedAnalyticsServiceServer) GetFunnel(context.Context, *FunnelRequest) (*FunnelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFunnel not implemented")
+}
+func (UnimplementedAnalyticsServiceServer) GetRetention(context.Context, *RetentionRequest) (*RetentionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRetention not implemented")
+}
+func (UnimplementedAnalyticsServiceServer) GetPaths(context.Context, *PathsRequest) (*PathsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPaths not implemented")
+}
+func (UnimplementedAnalyticsServiceServer) SubscribeCounts(*SubscribeCountsRequest, grpc.ServerStreamingServer[CountUpdate]) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeCounts not implemented")
+}
 func (UnimplementedAnalyticsServiceServer) mustEmbedUnimplementedAnalyticsServiceServer() {}
 func (UnimplementedAnalyticsServiceServer) testEmbeddedByValue()                          {}
 
@@ -104,6 +289,197 @@ func _AnalyticsService_GetEventCount_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AnalyticsService_GetPropertyCounts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PropertyCountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AnalyticsServiceServer).GetPropertyCounts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AnalyticsService_GetPropertyCounts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AnalyticsServiceServer).GetPropertyCounts(ctx, req.(*PropertyCountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AnalyticsService_GetTimeSeries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TimeSeriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AnalyticsServiceServer).GetTimeSeries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AnalyticsService_GetTimeSeries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AnalyticsServiceServer).GetTimeSeries(ctx, req.(*TimeSeriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AnalyticsService_TopPages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TopRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AnalyticsServiceServer).TopPages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AnalyticsService_TopPages_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AnalyticsServiceServer).TopPages(ctx, req.(*TopRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AnalyticsService_TopUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TopRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AnalyticsServiceServer).TopUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AnalyticsService_TopUsers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AnalyticsServiceServer).TopUsers(ctx, req.(*TopRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AnalyticsService_GetUniqueUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UniqueUsersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AnalyticsServiceServer).GetUniqueUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AnalyticsService_GetUniqueUsers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AnalyticsServiceServer).GetUniqueUsers(ctx, req.(*UniqueUsersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AnalyticsService_GetSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AnalyticsServiceServer).GetSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AnalyticsService_GetSessions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AnalyticsServiceServer).GetSessions(ctx, req.(*SessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AnalyticsService_GetSessionStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SessionStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AnalyticsServiceServer).GetSessionStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AnalyticsService_GetSessionStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AnalyticsServiceServer).GetSessionStats(ctx, req.(*SessionStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AnalyticsService_GetFunnel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FunnelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AnalyticsServiceServer).GetFunnel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AnalyticsService_GetFunnel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AnalyticsServiceServer).GetFunnel(ctx, req.(*FunnelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AnalyticsService_GetRetention_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RetentionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AnalyticsServiceServer).GetRetention(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AnalyticsService_GetRetention_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AnalyticsServiceServer).GetRetention(ctx, req.(*RetentionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AnalyticsService_GetPaths_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PathsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AnalyticsServiceServer).GetPaths(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AnalyticsService_GetPaths_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AnalyticsServiceServer).GetPaths(ctx, req.(*PathsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AnalyticsService_SubscribeCounts_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeCountsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AnalyticsServiceServer).SubscribeCounts(m, &grpc.GenericServerStream[SubscribeCountsRequest, CountUpdate]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AnalyticsService_SubscribeCountsServer = grpc.ServerStreamingServer[CountUpdate]
+
 // AnalyticsService_ServiceDesc is the grpc.ServiceDesc for AnalyticsService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -115,7 +491,53 @@ var AnalyticsService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetEventCount",
 			Handler:    _AnalyticsService_GetEventCount_Handler,
 		},
+		{
+			MethodName: "GetPropertyCounts",
+			Handler:    _AnalyticsService_GetPropertyCounts_Handler,
+		},
+		{
+			MethodName: "GetTimeSeries",
+			Handler:    _AnalyticsService_GetTimeSeries_Handler,
+		},
+		{
+			MethodName: "TopPages",
+			Handler:    _AnalyticsService_TopPages_Handler,
+		},
+		{
+			MethodName: "TopUsers",
+			Handler:    _AnalyticsService_TopUsers_Handler,
+		},
+		{
+			MethodName: "GetUniqueUsers",
+			Handler:    _AnalyticsService_GetUniqueUsers_Handler,
+		},
+		{
+			MethodName: "GetSessions",
+			Handler:    _AnalyticsService_GetSessions_Handler,
+		},
+		{
+			MethodName: "GetSessionStats",
+			Handler:    _AnalyticsService_GetSessionStats_Handler,
+		},
+		{
+			MethodName: "GetFunnel",
+			Handler:    _AnalyticsService_GetFunnel_Handler,
+		},
+		{
+			MethodName: "GetRetention",
+			Handler:    _AnalyticsService_GetRetention_Handler,
+		},
+		{
+			MethodName: "GetPaths",
+			Handler:    _AnalyticsService_GetPaths_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeCounts",
+			Handler:       _AnalyticsService_SubscribeCounts_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "analytics.proto",
 }
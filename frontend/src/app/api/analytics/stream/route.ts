@@ -0,0 +1,37 @@
+// Next.js API Route - Proxy the gateway's Server-Sent Events to avoid CORS issues
+import { NextRequest, NextResponse } from 'next/server';
+
+const BACKEND_URL = process.env.BACKEND_URL || 'http://localhost:30081';
+
+// never cache or pre-render a stream
+export const dynamic = 'force-dynamic';
+
+export async function GET(request: NextRequest) {
+  // Always use user_123 for demo
+  const userId = 'user_123';
+
+  try {
+    const upstream = await fetch(`${BACKEND_URL}/analytics/stream?user_id=${encodeURIComponent(userId)}`, {
+      headers: { Accept: 'text/event-stream' },
+      signal: request.signal,
+      cache: 'no-store',
+    });
+    if (!upstream.ok || !upstream.body) {
+      return NextResponse.json(
+        { error: 'Failed to subscribe to backend' },
+        { status: upstream.status || 502 }
+      );
+    }
+
+    return new Response(upstream.body, {
+      headers: {
+        'Content-Type': 'text/event-stream',
+        'Cache-Control': 'no-cache',
+        Connection: 'keep-alive',
+      },
+    });
+  } catch (error: any) {
+    console.error('Stream proxy error:', error);
+    return NextResponse.json({ error: 'Failed to subscribe to backend' }, { status: 502 });
+  }
+}
@@ -1,6 +1,6 @@
 'use client';
 
-import { useEffect, useState } from 'react';
+import { useEffect, useRef, useState } from 'react';
 import {
   Chart as ChartJS,
   CategoryScale,
@@ -35,6 +35,28 @@ interface AnalyticsData {
   pages: PageStats[];
 }
 
+// Sent by /api/analytics/stream for every processed batch that changed the user's counts
+interface CountUpdate {
+  delta: number;
+  changes: { user_id: string; page_url: string; event_type: string; delta: number }[];
+  published_at: string;
+}
+
+// Counts after a streamed update
+function addUpdate(data: AnalyticsData, update: CountUpdate): AnalyticsData {
+  const pages = data.pages.map(p => ({ ...p }));
+  for (const change of update.changes) {
+    const page = pages.find(p => p.page_url === change.page_url);
+    if (page) {
+      page.count += change.delta;
+    } else {
+      pages.push({ page_url: change.page_url, count: change.delta });
+    }
+  }
+  pages.sort((a, b) => b.count - a.count);
+  return { ...data, total_clicks: data.total_clicks + update.delta, pages };
+}
+
 // Static user for demo
 const DEMO_USER_ID = 'user_123';
 
@@ -43,29 +65,75 @@ export default function Dashboard() {
   const [loading, setLoading] = useState(true);
   const [error, setError] = useState<string | null>(null);
   const [lastUpdated, setLastUpdated] = useState<Date>(new Date());
+  const [live, setLive] = useState(false);
+
+  // Updates received while a fetch is in flight, replayed on top of its result.
+  // null when no fetch is running and updates apply directly.
+  const pending = useRef<CountUpdate[] | null>(null);
 
   const fetchData = async () => {
+    pending.current = [];
     try {
       setLoading(true);
       const response = await fetch('/api/analytics');
       if (!response.ok) throw new Error('Failed to fetch data');
-      const result = await response.json();
-      setData(result);
-      setLastUpdated(new Date());
+      const result: AnalyticsData = await response.json();
+      const buffered = pending.current ?? [];
+      setData(buffered.reduce(addUpdate, result));
+      setLastUpdated(buffered.length > 0 ? new Date(buffered[buffered.length - 1].published_at) : new Date());
       setError(null);
     } catch (err) {
       setError('Failed to connect to backend');
       console.error(err);
     } finally {
+      pending.current = null;
       setLoading(false);
     }
   };
 
+  // Add a streamed update to the counts on screen
+  const applyUpdate = (update: CountUpdate) => {
+    if (pending.current) {
+      pending.current.push(update);
+      return;
+    }
+    setData(prev => prev && addUpdate(prev, update));
+    setLastUpdated(new Date(update.published_at));
+  };
+
   useEffect(() => {
-    fetchData();
-    // Auto-refresh every 30 seconds
-    const interval = setInterval(fetchData, 30000);
-    return () => clearInterval(interval);
+    // Updates are deltas: the counts are loaded once the stream is open, so no update
+    // falls between the two, and reloaded after reconnecting, since batches published
+    // while disconnected are missed
+    let opened = false;
+    let dropped = false;
+    let source: EventSource;
+    let retry: ReturnType<typeof setTimeout> | undefined;
+    const connect = () => {
+      source = new EventSource('/api/analytics/stream');
+      source.onopen = () => {
+        setLive(true);
+        if (!opened || dropped) fetchData();
+        opened = true;
+        dropped = false;
+      };
+      source.onerror = () => {
+        setLive(false);
+        // still show the counts when the stream can't be opened at all
+        if (!opened && !dropped) fetchData();
+        dropped = true;
+        // EventSource retries a dropped stream itself, but gives up on an error status
+        if (source.readyState === EventSource.CLOSED) retry = setTimeout(connect, 3000);
+      };
+      source.addEventListener('counts', (event) => {
+        applyUpdate(JSON.parse((event as MessageEvent).data));
+      });
+    };
+    connect();
+    return () => {
+      clearTimeout(retry);
+      source.close();
+    };
   }, []);
 
   // Chart data
@@ -327,7 +395,7 @@ export default function Dashboard() {
         color: 'var(--text-secondary)'
       }}>
         <span>📡 Connected to API Gateway at <code style={{ background: '#f3f4f6', padding: '2px 6px', borderRadius: '4px' }}>localhost:30081</code></span>
-        <span>User: <strong>{DEMO_USER_ID}</strong> • Auto-refresh: 30s</span>
+        <span>User: <strong>{DEMO_USER_ID}</strong> • {live ? '🟢 Live' : '⚪ Reconnecting...'}</span>
       </div>
     </div>
   );
@@ -3,12 +3,199 @@ CREATE TABLE IF NOT EXISTS click_events (
     user_id    TEXT NOT NULL,
     event_type TEXT NOT NULL,
     page_url   TEXT NOT NULL,
-    time_stamp TIMESTAMP WITH TIME ZONE NOT NULL
+    time_stamp TIMESTAMP WITH TIME ZONE NOT NULL,
+    properties JSONB NOT NULL DEFAULT '{}'::jsonb
 );
 
+-- databases created before custom properties existed
+ALTER TABLE click_events ADD COLUMN IF NOT EXISTS properties JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+-- serves the ? (has key) and @> (contains) filters used by GetPropertyCounts
+CREATE INDEX IF NOT EXISTS click_events_properties_idx ON click_events USING GIN (properties);
+
+-- serves the time range scans of GetFunnel
+CREATE INDEX IF NOT EXISTS click_events_time_idx ON click_events (time_stamp);
+
+-- serves GetPaths, which reads each session's events by user and time
+CREATE INDEX IF NOT EXISTS click_events_user_time_idx ON click_events (user_id, time_stamp);
+
 CREATE TABLE IF NOT EXISTS page_clicks (
     user_id     TEXT NOT NULL,
     page_url    TEXT NOT NULL,
+    event_type  TEXT NOT NULL DEFAULT '',
     click_count INT DEFAULT 0,
-    PRIMARY KEY (user_id, page_url)
-);
\ No newline at end of file
+    PRIMARY KEY (user_id, page_url, event_type)
+);
+
+-- databases created before the aggregates were split by event_type: widen the key once and
+-- rebuild the counts from click_events so existing rows get their real event_type
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'page_clicks' AND column_name = 'event_type'
+    ) THEN
+        LOCK TABLE page_clicks IN ACCESS EXCLUSIVE MODE;
+        ALTER TABLE page_clicks ADD COLUMN event_type TEXT NOT NULL DEFAULT '';
+        ALTER TABLE page_clicks DROP CONSTRAINT page_clicks_pkey;
+        ALTER TABLE page_clicks ADD PRIMARY KEY (user_id, page_url, event_type);
+        DELETE FROM page_clicks;
+        INSERT INTO page_clicks (user_id, page_url, event_type, click_count)
+        SELECT user_id, page_url, event_type, COUNT(*)
+        FROM click_events
+        GROUP BY user_id, page_url, event_type;
+    END IF;
+
+    -- the totals are derived from page_clicks, recreate them below with the new key
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'page_totals' AND column_name = 'page_url'
+    ) AND NOT EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'page_totals' AND column_name = 'event_type'
+    ) THEN
+        DROP TABLE IF EXISTS page_totals;
+        DROP TABLE IF EXISTS user_totals;
+    END IF;
+END $$;
+
+-- time-bucketed rollups, bucket is the event time_stamp truncated in UTC. The processor
+-- deletes buckets past ROLLUP_RETENTION_MINUTE / _HOUR / _DAY.
+CREATE TABLE IF NOT EXISTS clicks_per_minute (
+    bucket      TIMESTAMP WITH TIME ZONE NOT NULL,
+    page_url    TEXT NOT NULL,
+    user_id     TEXT NOT NULL,
+    event_type  TEXT NOT NULL,
+    click_count BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (bucket, page_url, user_id, event_type)
+);
+
+CREATE TABLE IF NOT EXISTS clicks_per_hour (
+    bucket      TIMESTAMP WITH TIME ZONE NOT NULL,
+    page_url    TEXT NOT NULL,
+    user_id     TEXT NOT NULL,
+    event_type  TEXT NOT NULL,
+    click_count BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (bucket, page_url, user_id, event_type)
+);
+
+CREATE TABLE IF NOT EXISTS clicks_per_day (
+    bucket      TIMESTAMP WITH TIME ZONE NOT NULL,
+    page_url    TEXT NOT NULL,
+    user_id     TEXT NOT NULL,
+    event_type  TEXT NOT NULL,
+    click_count BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (bucket, page_url, user_id, event_type)
+);
+
+-- one-off data migrations already applied, so that re-running this file doesn't repeat them
+CREATE TABLE IF NOT EXISTS schema_migrations (
+    name       TEXT PRIMARY KEY,
+    applied_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+-- databases with events from before the rollups existed: rebuild clicks_per_day from
+-- click_events once, so GetRetention and GetTimeSeries see the whole history. The marker
+-- keeps it from running again once day retention has pruned the oldest buckets, which would
+-- bring them back. Databases that predate the marker skip it when the day of the oldest
+-- event has a bucket. The lock holds the processor's rollup writes until the rebuild
+-- commits; events it stored before are counted here, the ones it is storing are invisible
+-- here and added by it afterwards.
+DO $$
+BEGIN
+    INSERT INTO schema_migrations (name) VALUES ('clicks_per_day_backfill') ON CONFLICT (name) DO NOTHING;
+    IF NOT FOUND THEN
+        RETURN;
+    END IF;
+
+    IF EXISTS (SELECT 1 FROM click_events) AND NOT EXISTS (
+        SELECT 1 FROM clicks_per_day
+        WHERE bucket = (SELECT date_trunc('day', MIN(time_stamp), 'UTC') FROM click_events)
+    ) THEN
+        LOCK TABLE clicks_per_day IN EXCLUSIVE MODE;
+        DELETE FROM clicks_per_day;
+        INSERT INTO clicks_per_day (bucket, page_url, user_id, event_type, click_count)
+        SELECT date_trunc('day', time_stamp, 'UTC'), page_url, user_id, event_type, COUNT(*)
+        FROM click_events
+        GROUP BY 1, page_url, user_id, event_type;
+    END IF;
+END $$;
+
+-- GetTimeSeries filters the rollups by page or user over a bucket range
+CREATE INDEX IF NOT EXISTS clicks_per_minute_page_idx ON clicks_per_minute (page_url, bucket);
+CREATE INDEX IF NOT EXISTS clicks_per_minute_user_idx ON clicks_per_minute (user_id, bucket);
+CREATE INDEX IF NOT EXISTS clicks_per_hour_page_idx ON clicks_per_hour (page_url, bucket);
+CREATE INDEX IF NOT EXISTS clicks_per_hour_user_idx ON clicks_per_hour (user_id, bucket);
+CREATE INDEX IF NOT EXISTS clicks_per_day_page_idx ON clicks_per_day (page_url, bucket);
+CREATE INDEX IF NOT EXISTS clicks_per_day_user_idx ON clicks_per_day (user_id, bucket);
+
+-- totals across users and across pages, served by GetEventCount when user_id or page_url is omitted
+CREATE TABLE IF NOT EXISTS page_totals (
+    page_url    TEXT NOT NULL,
+    event_type  TEXT NOT NULL,
+    click_count BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (page_url, event_type)
+);
+
+CREATE TABLE IF NOT EXISTS user_totals (
+    user_id     TEXT NOT NULL,
+    event_type  TEXT NOT NULL,
+    click_count BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_id, event_type)
+);
+
+-- seed them from page_clicks on databases that predate them, a no-op once populated
+INSERT INTO page_totals (page_url, event_type, click_count)
+SELECT page_url, event_type, SUM(click_count) FROM page_clicks GROUP BY page_url, event_type
+ON CONFLICT (page_url, event_type) DO NOTHING;
+
+INSERT INTO user_totals (user_id, event_type, click_count)
+SELECT user_id, event_type, SUM(click_count) FROM page_clicks GROUP BY user_id, event_type
+ON CONFLICT (user_id, event_type) DO NOTHING;
+
+-- one row per session, upserted by the processor while it is open and final once ended_at is set
+CREATE TABLE IF NOT EXISTS sessions (
+    session_id   TEXT PRIMARY KEY,
+    user_id      TEXT NOT NULL,
+    started_at   TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ended_at     TIMESTAMP WITH TIME ZONE,
+    duration_ms  BIGINT NOT NULL DEFAULT 0,
+    event_count  INT NOT NULL,
+    page_count   INT NOT NULL,
+    entry_page   TEXT NOT NULL,
+    exit_page    TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS sessions_user_idx ON sessions (user_id, started_at DESC);
+CREATE INDEX IF NOT EXISTS sessions_started_idx ON sessions (started_at);
+-- the processor's sweep looks for open rows whose Redis state expired
+CREATE INDEX IF NOT EXISTS sessions_open_idx ON sessions (last_seen_at, session_id) WHERE ended_at IS NULL;
+
+-- when each user was first seen doing each event_type, and doing anything under event_type ''.
+-- GetRetention groups users into cohorts by it.
+CREATE TABLE IF NOT EXISTS user_first_seen (
+    user_id    TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    first_seen TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (user_id, event_type)
+);
+
+CREATE INDEX IF NOT EXISTS user_first_seen_cohort_idx ON user_first_seen (event_type, first_seen);
+
+-- seed it from click_events once on databases that predate it
+DO $$
+BEGIN
+    INSERT INTO schema_migrations (name) VALUES ('user_first_seen_backfill') ON CONFLICT (name) DO NOTHING;
+    IF NOT FOUND THEN
+        RETURN;
+    END IF;
+
+    INSERT INTO user_first_seen (user_id, event_type, first_seen)
+    SELECT user_id, event_type, MIN(time_stamp) FROM click_events GROUP BY user_id, event_type
+    ON CONFLICT (user_id, event_type) DO NOTHING;
+
+    INSERT INTO user_first_seen (user_id, event_type, first_seen)
+    SELECT user_id, '', MIN(time_stamp) FROM click_events GROUP BY user_id
+    ON CONFLICT (user_id, event_type) DO NOTHING;
+END $$;